@@ -5,8 +5,11 @@ package kind
 
 import (
 	"fmt"
+	"io/ioutil"
 	"os"
+	"strconv"
 	"strings"
+	"time"
 
 	cfgutil "github.com/k8ssandra/cass-operator/mage/config"
 	dockerutil "github.com/k8ssandra/cass-operator/mage/docker"
@@ -28,11 +31,97 @@ var ClusterActions = cfgutil.NewClusterActions(
 )
 
 const (
-	kindConfigPath = "M_KIND_CONFIG"
+	kindConfigPath   = "M_KIND_CONFIG"
+	kindWorkerCount  = "M_KIND_WORKERS"
+	defaultWorkers   = 6
+	kindRetries      = "M_KIND_CREATE_RETRIES"
+	defaultRetries   = 5
+	kindBackoff      = "M_KIND_CREATE_BACKOFF"
+	defaultBackoff   = 10 * time.Second
+	kindConfigHeader = `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+networking:
+  apiServerPort: 45451
+nodes:
+- role: control-plane
+`
 )
 
+// renderKindConfig returns a kind cluster config with a control plane and the given
+// number of worker nodes
+func renderKindConfig(workers int) string {
+	config := kindConfigHeader
+	for i := 0; i < workers; i++ {
+		config += "- role: worker\n"
+	}
+	return config
+}
+
+// writeKindConfig writes a kind cluster config with the given number of worker nodes
+// to a temp file and returns its path
+func writeKindConfig(workers int) (string, error) {
+	if workers < 1 {
+		return "", fmt.Errorf("kind cluster requires at least one worker, got %d", workers)
+	}
+
+	file, err := ioutil.TempFile("", fmt.Sprintf("kind_config_%d_workers_*.yaml", workers))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(renderKindConfig(workers)); err != nil {
+		return "", err
+	}
+	return file.Name(), nil
+}
+
+// getKindConfig returns the path of the kind config set in M_KIND_CONFIG, or otherwise
+// renders one with the number of workers set in M_KIND_WORKERS, defaulting to six
+func getKindConfig() string {
+	if config, ok := os.LookupEnv(kindConfigPath); ok {
+		return config
+	}
+
+	workers := defaultWorkers
+	if value, ok := os.LookupEnv(kindWorkerCount); ok {
+		var err error
+		workers, err = strconv.Atoi(value)
+		mageutil.PanicOnError(err)
+	}
+
+	config, err := writeKindConfig(workers)
+	mageutil.PanicOnError(err)
+	return config
+}
+
+// getCreateRetries returns how often creating the cluster is retried, set in
+// M_KIND_CREATE_RETRIES, and the backoff before the first retry, set in
+// M_KIND_CREATE_BACKOFF, which doubles with every further retry
+func getCreateRetries() (int, time.Duration) {
+	retries := defaultRetries
+	if value, ok := os.LookupEnv(kindRetries); ok {
+		var err error
+		retries, err = strconv.Atoi(value)
+		mageutil.PanicOnError(err)
+	}
+
+	backoff := defaultBackoff
+	if value, ok := os.LookupEnv(kindBackoff); ok {
+		var err error
+		backoff, err = time.ParseDuration(value)
+		mageutil.PanicOnError(err)
+	}
+	return retries, backoff
+}
+
 func describeEnv() map[string]string {
-	return make(map[string]string)
+	return map[string]string{
+		"M_KIND_CONFIG":         "Path of the kind cluster config. If not set, one is generated with M_KIND_WORKERS workers.",
+		"M_KIND_WORKERS":        "Number of worker nodes of the generated kind cluster config. Defaults to 6",
+		"M_KIND_CREATE_RETRIES": "Number of times creating the kind cluster is retried. Defaults to 5",
+		"M_KIND_CREATE_BACKOFF": "Wait before the first retry of creating the kind cluster, doubling with every retry. Defaults to 10s",
+	}
 }
 
 func applyDefaultStorage() {
@@ -54,14 +143,14 @@ func clusterExists() bool {
 }
 
 func createCluster() {
-	config := mageutil.FromEnvOrDefault(kindConfigPath, "tests/testdata/kind/kind_config_6_workers.yaml")
+	config := getKindConfig()
 
 	// Kind can be flaky when starting up a new cluster
 	// so let's give it a few chances to redeem itself
 	// after failing
-	retries := 5
+	retries, backoff := getCreateRetries()
 	var err error
-	for retries > 0 {
+	for {
 		// We explicitly request a kubernetes v1.15 cluster with --image
 		err = shutil.RunV(
 			"kind",
@@ -74,12 +163,13 @@ func createCluster() {
 			"--wait", "600s",
 		)
 
-		if err != nil {
-			fmt.Printf("KIND failed to create the cluster. %v retries left.\n", retries)
-			retries--
-		} else {
-			return
+		if err == nil || retries <= 0 {
+			break
 		}
+		fmt.Printf("KIND failed to create the cluster. %v retries left, retrying in %v.\n", retries, backoff)
+		retries--
+		time.Sleep(backoff)
+		backoff *= 2
 	}
 	mageutil.PanicOnError(err)
 }
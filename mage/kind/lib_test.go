@@ -0,0 +1,56 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package kind
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_renderKindConfigMatchesStaticConfig(t *testing.T) {
+	staticConfigs := map[string]int{
+		"kind_config_1_worker.yaml":  1,
+		"kind_config_3_workers.yaml": 3,
+		"kind_config_6_workers.yaml": 6,
+	}
+	for file, workers := range staticConfigs {
+		expected, err := ioutil.ReadFile("../../tests/testdata/kind/" + file)
+		require.NoError(t, err)
+		assert.Equal(t, string(expected), renderKindConfig(workers), file)
+	}
+}
+
+func Test_writeKindConfig(t *testing.T) {
+	path, err := writeKindConfig(2)
+	require.NoError(t, err)
+	defer os.Remove(path)
+
+	content, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, renderKindConfig(2), string(content))
+
+	_, err = writeKindConfig(0)
+	assert.Error(t, err)
+}
+
+func Test_getCreateRetries(t *testing.T) {
+	os.Unsetenv(kindRetries)
+	os.Unsetenv(kindBackoff)
+	retries, backoff := getCreateRetries()
+	assert.Equal(t, 5, retries)
+	assert.Equal(t, 10*time.Second, backoff)
+
+	os.Setenv(kindRetries, "2")
+	os.Setenv(kindBackoff, "1m")
+	defer os.Unsetenv(kindRetries)
+	defer os.Unsetenv(kindBackoff)
+	retries, backoff = getCreateRetries()
+	assert.Equal(t, 2, retries)
+	assert.Equal(t, time.Minute, backoff)
+}
@@ -0,0 +1,62 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package operator
+
+import (
+	"fmt"
+	"os"
+
+	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	"k8s.io/apimachinery/pkg/util/yaml"
+)
+
+const envManifest = "MO_MANIFEST"
+
+// readDatacenterManifest reads a CassandraDatacenter from a YAML or JSON manifest
+func readDatacenterManifest(path string) (*api.CassandraDatacenter, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	dc := &api.CassandraDatacenter{}
+	if err := yaml.NewYAMLOrJSONDecoder(file, 4096).Decode(dc); err != nil {
+		return nil, fmt.Errorf("could not parse %s: %v", path, err)
+	}
+	if dc.Kind != "CassandraDatacenter" {
+		return nil, fmt.Errorf("%s holds a %s rather than a CassandraDatacenter", path, dc.Kind)
+	}
+	return dc, nil
+}
+
+// Validates a CassandraDatacenter manifest with the checks
+// the validating webhook runs on creation.
+//
+// Set env variable MO_MANIFEST to the path of the manifest.
+// Warnings are printed, and the target fails if the webhook
+// would reject the manifest. Checks which need a k8s cluster,
+// such as whether a worker node fits the resource requests,
+// are skipped.
+func ValidateManifest() error {
+	path := os.Getenv(envManifest)
+	if path == "" {
+		return fmt.Errorf("set %s to the path of a CassandraDatacenter manifest", envManifest)
+	}
+
+	dc, err := readDatacenterManifest(path)
+	if err != nil {
+		return err
+	}
+
+	for _, warning := range api.GetValidationWarnings(*dc) {
+		fmt.Printf("WARNING: %s\n", warning)
+	}
+
+	if err := api.ValidateSingleDatacenter(*dc); err != nil {
+		return err
+	}
+	fmt.Printf("%s is a valid CassandraDatacenter\n", path)
+	return nil
+}
@@ -0,0 +1,52 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package operator
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMageOperator_ValidateManifest_examples(t *testing.T) {
+	examples := []string{
+		"../../operator/example-cassdc-yaml/cassandra-3.11.x/example-cassdc-full.yaml",
+		"../../operator/example-cassdc-yaml/dse-6.8.x/example-cassdc-full.yaml",
+	}
+	defer os.Unsetenv(envManifest)
+	for _, example := range examples {
+		os.Setenv(envManifest, example)
+		assert.NoError(t, ValidateManifest(), example)
+	}
+}
+
+func TestMageOperator_ValidateManifest_invalid(t *testing.T) {
+	file, err := ioutil.TempFile("", "invalid-cassdc-*.yaml")
+	require.NoError(t, err)
+	defer os.Remove(file.Name())
+	_, err = file.WriteString(`apiVersion: cassandra.datastax.com/v1beta1
+kind: CassandraDatacenter
+metadata:
+  name: dc1
+spec:
+  clusterName: cluster1
+  serverType: cassandra
+  serverVersion: "3.11.7"
+  size: 3
+  seedsPerRack: -1
+`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	defer os.Unsetenv(envManifest)
+	os.Setenv(envManifest, file.Name())
+	err = ValidateManifest()
+	assert.EqualError(t, err, "CassandraDatacenter write rejected, attempted to use seedsPerRack -1, at least one seed per rack is required")
+
+	os.Setenv(envManifest, "../../operator/deploy/crds/cassandra.datastax.com_cassandradatacenters_crd.yaml")
+	assert.Error(t, ValidateManifest())
+}
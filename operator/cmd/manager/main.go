@@ -192,12 +192,22 @@ func main() {
 		os.Exit(1)
 	}
 
+	if objectSizeWarningEnvVal := os.Getenv("OBJECT_SIZE_WARNING_BYTES"); objectSizeWarningEnvVal != "" {
+		api.ObjectSizeWarningBytes, err = strconv.Atoi(objectSizeWarningEnvVal)
+		if err != nil {
+			log.Error(err, "bad value for OBJECT_SIZE_WARNING_BYTES env")
+			os.Exit(1)
+		}
+	}
+
 	if !skipWebhook {
 		err = controllerRuntime.NewWebhookManagedBy(mgr).For(&api.CassandraDatacenter{}).Complete()
 		if err != nil {
 			log.Error(err, "unable to create validating webhook for CassandraDatacenter")
 			os.Exit(1)
 		}
+		api.WebhookNodeLister = webhook.ClientNodeLister{Reader: mgr.GetAPIReader()}
+		api.WebhookPodLister = webhook.ClientPodLister{Reader: mgr.GetAPIReader()}
 	}
 
 	// Add the Metrics Service
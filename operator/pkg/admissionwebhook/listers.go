@@ -0,0 +1,37 @@
+package webhook
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ClientNodeLister lists the cluster's nodes for the validating webhook's
+// topology warnings. It should be given an uncached reader, since nodes are
+// cluster scoped and the manager's cache only watches the operator namespace.
+type ClientNodeLister struct {
+	Reader crclient.Reader
+}
+
+func (l ClientNodeLister) ListNodes() ([]corev1.Node, error) {
+	nodeList := &corev1.NodeList{}
+	if err := l.Reader.List(context.Background(), nodeList); err != nil {
+		return nil, err
+	}
+	return nodeList.Items, nil
+}
+
+// ClientPodLister lists the pods of a namespace for the validating webhook's
+// PVC and pod affinity warnings.
+type ClientPodLister struct {
+	Reader crclient.Reader
+}
+
+func (l ClientPodLister) ListPods(namespace string) ([]corev1.Pod, error) {
+	podList := &corev1.PodList{}
+	if err := l.Reader.List(context.Background(), podList, crclient.InNamespace(namespace)); err != nil {
+		return nil, err
+	}
+	return podList.Items, nil
+}
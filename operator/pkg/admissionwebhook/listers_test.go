@@ -0,0 +1,33 @@
+package webhook
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+)
+
+func TestClientListers(t *testing.T) {
+	reader := fake.NewFakeClient(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-2"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-1", Namespace: "ns1"}},
+		&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: "pod-2", Namespace: "ns2"}},
+	)
+
+	var nodeLister api.NodeLister = ClientNodeLister{Reader: reader}
+	nodes, err := nodeLister.ListNodes()
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 2)
+
+	var podLister api.PodLister = ClientPodLister{Reader: reader}
+	pods, err := podLister.ListPods("ns1")
+	assert.NoError(t, err)
+	if assert.Len(t, pods, 1) {
+		assert.Equal(t, "pod-1", pods[0].Name)
+	}
+}
@@ -4,15 +4,27 @@
 package v1beta1
 
 import (
+	"crypto/sha256"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/Jeffail/gabs"
+	"github.com/k8ssandra/cass-operator/operator/pkg/images"
+	"github.com/k8ssandra/cass-operator/operator/pkg/oplabels"
 	"github.com/k8ssandra/cass-operator/operator/pkg/serverconfig"
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 const (
@@ -40,6 +52,21 @@ const (
 	// ConfigHashAnnotation is the operator's annotation for the hash of the ConfigSecret
 	ConfigHashAnnotation = "cassandra.datastax.com/config-hash"
 
+	// CleanedUpAfterScalingAnnotation is the pod annotation holding the start of the scale
+	// up after which the keyspace cleanup of the pod's node completed
+	CleanedUpAfterScalingAnnotation = "cassandra.datastax.com/cleaned-up-after-scaling"
+
+	// Finalizer is the finalizer the operator sets on a CassandraDatacenter, so that it
+	// can delete the PVCs and release the watches of the datacenter before it is gone.
+	// Removing it by hand skips that cleanup. The value must stay stable, as it is stored
+	// on existing datacenters.
+	Finalizer = "finalizer.cassandra.datastax.com"
+
+	// SuperuserSecretUsernameKey and SuperuserSecretPasswordKey are the keys of the
+	// superuser secret holding the credentials
+	SuperuserSecretUsernameKey = "username"
+	SuperuserSecretPasswordKey = "password"
+
 	// CassNodeState
 	CassNodeState = "cassandra.datastax.com/node-state"
 
@@ -48,10 +75,131 @@ const (
 	ProgressReady    ProgressState = "Ready"
 
 	// Default port numbers
-	DefaultNativePort    = 9042
-	DefaultInternodePort = 7000
+	DefaultNativePort       = 9042
+	DefaultInternodePort    = 7000
+	DefaultInternodeSSLPort = 7001
+	DefaultPrometheusPort   = 9103
+
+	// DefaultDataDirectory is where the server data volume is mounted unless
+	// StorageConfig.DataDirectory is set
+	DefaultDataDirectory = "/var/lib/cassandra"
+
+	// DefaultConfigMountPath is where the rendered server config is mounted unless
+	// ConfigMountPath is set
+	DefaultConfigMountPath = "/config"
+
+	// DefaultServerLogDirectory is where the server logs volume is mounted
+	DefaultServerLogDirectory = "/var/log/cassandra"
+
+	// DefaultGCLogPath is where the JVM writes the GC log when EnableGCLogging is set
+	// without GCLogPath
+	DefaultGCLogPath = DefaultServerLogDirectory + "/gc.log"
+)
+
+// ReservedConfigKeys lists the config paths, in dot notation, which are managed by the
+// operator and may not be set through the user config unless ForceConfigOverride or
+// DisableConfigValidation is set. The config builder sets listen_address to the pod
+// address, which the operator deletes again when a listen interface is used.
+var ReservedConfigKeys = []string{
+	"cluster-info.name",
+	"cluster-info.seeds",
+	"datacenter-info.name",
+	"cassandra-yaml.cluster_name",
+	"cassandra-yaml.seed_provider",
+	"cassandra-yaml.listen_address",
+	"cassandra-rackdc-properties.dc",
+	"cassandra-rackdc-properties.rack",
+}
+
+// Values of RackDistributionStrategy
+const (
+	RackDistributionFirstRacks     = "FirstRacks"
+	RackDistributionLastRacks      = "LastRacks"
+	RackDistributionPreferredRacks = "PreferredRacks"
+)
+
+// Values of NodeReplacementStrategy
+const (
+	NodeReplacementReplace = "Replace"
+	NodeReplacementRestart = "Restart"
+)
+
+// Values of ManagementApiMode
+const (
+	ManagementApiModeInContainer = "InContainer"
+	ManagementApiModeSidecar     = "Sidecar"
+)
+
+// ManagementApiModes lists the supported values of ManagementApiMode
+var ManagementApiModes = []string{
+	ManagementApiModeInContainer,
+	ManagementApiModeSidecar,
+}
+
+// Values of AntiAffinityScope
+const (
+	AntiAffinityScopeDatacenter = "Datacenter"
+	AntiAffinityScopeCluster    = "Cluster"
+)
+
+// AntiAffinityScopes lists the supported values of AntiAffinityScope
+var AntiAffinityScopes = []string{
+	AntiAffinityScopeDatacenter,
+	AntiAffinityScopeCluster,
+}
+
+const (
+	// ServerContainerName is the name of the server container of the pods
+	ServerContainerName = "cassandra"
+	// ManagementApiContainerName is the name of the management API container of the pods
+	// in ManagementApiModeSidecar
+	ManagementApiContainerName = "management-api"
+	// ManagementApiPortName is the name of the management API container port
+	ManagementApiPortName = "mgmt-api-http"
 )
 
+// NodeReplacementStrategies lists the supported values of NodeReplacementStrategy
+var NodeReplacementStrategies = []string{
+	NodeReplacementReplace,
+	NodeReplacementRestart,
+}
+
+// RackDistributionStrategies lists the supported values of RackDistributionStrategy
+var RackDistributionStrategies = []string{
+	RackDistributionFirstRacks,
+	RackDistributionLastRacks,
+	RackDistributionPreferredRacks,
+}
+
+// LogLevels lists the supported values of LogLevel
+var LogLevels = []string{"ERROR", "WARN", "INFO", "DEBUG", "TRACE"}
+
+// versionedConfigKeys lists the config paths, in dot notation, which structured config
+// fields render and which only some server versions support, with the server types and
+// the version prefixes supporting them
+var versionedConfigKeys = map[string]map[string][]string{
+	// The group commitlog sync was added in Cassandra 4.0
+	"cassandra-yaml.commitlog_sync_group_window_in_ms": {"cassandra": {"4."}},
+}
+
+// LegacyRpcConfigKeys lists the thrift rpc settings of cassandra.yaml, in dot notation.
+// They are only supported by Cassandra 3.x, and Cassandra 4.0 and DSE 6.8 fail to start
+// when they are set, so they are stripped from the user config for those versions.
+var LegacyRpcConfigKeys = []string{
+	"cassandra-yaml.start_rpc",
+	"cassandra-yaml.rpc_port",
+	"cassandra-yaml.rpc_server_type",
+	"cassandra-yaml.rpc_min_threads",
+	"cassandra-yaml.rpc_max_threads",
+	"cassandra-yaml.rpc_send_buff_size_in_bytes",
+	"cassandra-yaml.rpc_recv_buff_size_in_bytes",
+	"cassandra-yaml.thrift_framed_transport_size_in_mb",
+	"cassandra-yaml.thrift_prepared_statements_cache_size_mb",
+	"cassandra-yaml.request_scheduler",
+	"cassandra-yaml.request_scheduler_id",
+	"cassandra-yaml.request_scheduler_options",
+}
+
 // This type exists so there's no chance of pushing random strings to our progress status
 type ProgressState string
 
@@ -110,6 +258,16 @@ type CassandraDatacenterSpec struct {
 	// that an update to the secret will trigger an update of the StatefulSets.
 	ConfigSecret string `json:"configSecret,omitempty"`
 
+	// Allow Config or ConfigSecret to set keys that are reserved for the operator, such as
+	// the cluster name, seeds, listen address and rack/dc properties. Only set this if you
+	// know what you are doing, as overriding these keys can break the datacenter.
+	ForceConfigOverride bool `json:"forceConfigOverride,omitempty"`
+
+	// Turn off the optional validations of Config and ConfigSecret, such as the reserved
+	// key checks, for configs that intentionally trip them. Config that cannot be parsed
+	// is still rejected.
+	DisableConfigValidation bool `json:"disableConfigValidation,omitempty"`
+
 	// Config for the Management API certificates
 	ManagementApiAuth ManagementApiAuthConfig `json:"managementApiAuth,omitempty"`
 
@@ -136,6 +294,16 @@ type CassandraDatacenterSpec struct {
 	// A list of pod names that need to be replaced.
 	ReplaceNodes []string `json:"replaceNodes,omitempty"`
 
+	// How the nodes of the pods in ReplaceNodes are started. Replace, the default, starts
+	// them with replace_address, so that they take over the tokens of the node and stream
+	// its data, for nodes whose data volume is lost. Restart only restarts them normally,
+	// so that they rejoin under their own host ID from what is left on their data volume,
+	// without streaming any data. Run nodetool rebuild or repair on them afterwards to
+	// recover what they missed. Restart is refused for pods whose data volume was created
+	// after the replacement started.
+	// +kubebuilder:validation:Enum=Replace;Restart
+	NodeReplacementStrategy string `json:"nodeReplacementStrategy,omitempty"`
+
 	// The name by which CQL clients and instances will know the cluster. If the same
 	// cluster name is shared by multiple Datacenters in the same Kubernetes namespace,
 	// they will join together in a multi-datacenter cluster.
@@ -150,6 +318,36 @@ type CassandraDatacenterSpec struct {
 	// Container image for the config builder init container.
 	ConfigBuilderImage string `json:"configBuilderImage,omitempty"`
 
+	// Turning this option on makes the webhook reject a Config which is structurally wrong
+	// for the config builder, e.g. with a section that is not a config file of the server
+	// version. This does not apply to ConfigSecret or with disableConfigValidation.
+	StrictConfigValidation bool `json:"strictConfigValidation,omitempty"`
+
+	// Pull policy of the server image. When not set, Kubernetes pulls an image without a
+	// tag or with the "latest" tag every time, and other images only if not present.
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ServerImagePullPolicy corev1.PullPolicy `json:"serverImagePullPolicy,omitempty"`
+
+	// Pull policy of the config builder image, defaulting like serverImagePullPolicy.
+	// +kubebuilder:validation:Enum=Always;Never;IfNotPresent
+	ConfigBuilderImagePullPolicy corev1.PullPolicy `json:"configBuilderImagePullPolicy,omitempty"`
+
+	// Turning this option on makes the operator log the config it passes to the config
+	// builder, with passwords and secrets redacted, whenever the config changes,
+	// to debug config rendering problems. The config builder has no verbose mode, so its
+	// own output is unchanged.
+	DebugConfigBuilder bool `json:"debugConfigBuilder,omitempty"`
+
+	// Turning this option on rejects a serverImage or configBuilderImage without an explicit
+	// tag or digest, which would pull "latest", rather than only logging a warning.
+	RequireImageTags bool `json:"requireImageTags,omitempty"`
+
+	// Absolute path where the volume holding the rendered server config is mounted, in both
+	// the config builder init container and the cassandra container. Server images reading
+	// their config from another directory can set this, while the config builder image has
+	// to write its output there. Defaults to /config.
+	ConfigMountPath string `json:"configMountPath,omitempty"`
+
 	// Indicates that configuration and container image changes should only be pushed to
 	// the first rack of the datacenter
 	CanaryUpgrade bool `json:"canaryUpgrade,omitempty"`
@@ -163,6 +361,26 @@ type CassandraDatacenterSpec struct {
 	// podAntiAffinity and requiredDuringSchedulingIgnoredDuringExecution.
 	AllowMultipleNodesPerWorker bool `json:"allowMultipleNodesPerWorker,omitempty"`
 
+	// Turning this option on makes the podAntiAffinity between server pods preferred rather
+	// than required, so pods are still spread over the k8s worker nodes when possible, but
+	// share one when there are fewer worker nodes than server pods. This is intended for
+	// development clusters, e.g. on kind, and must not be used in production, where losing
+	// a worker node could take down several replicas.
+	RelaxedScheduling bool `json:"relaxedScheduling,omitempty"`
+
+	// Node label key of the topology domain the podAntiAffinity between server pods keeps
+	// them apart in. Defaults to kubernetes.io/hostname, one server pod per worker node.
+	// A zone label allows only one server pod per zone, so the datacenter cannot have more
+	// nodes than there are zones.
+	AntiAffinityTopologyKey string `json:"antiAffinityTopologyKey,omitempty"`
+
+	// Server pods the podAntiAffinity keeps the server pods of the datacenter apart from.
+	// When unset, every server pod is considered, whichever cluster it belongs to.
+	// Datacenter only considers the pods of this datacenter, and Cluster the pods of all
+	// the datacenters of the cluster. Setting it rolls the pods.
+	// +kubebuilder:validation:Enum=Datacenter;Cluster
+	AntiAffinityScope string `json:"antiAffinityScope,omitempty"`
+
 	// This secret defines the username and password for the Cassandra server superuser.
 	// If it is omitted, we will generate a secret instead.
 	SuperuserSecretName string `json:"superuserSecretName,omitempty"`
@@ -189,6 +407,10 @@ type CassandraDatacenterSpec struct {
 	// PodTemplate provides customisation options (labels, annotations, affinity rules, resource requests, and so on) for the cassandra pods
 	PodTemplateSpec *corev1.PodTemplateSpec `json:"podTemplateSpec,omitempty"`
 
+	// Annotations added to the cassandra pods, e.g. for service meshes or backup tools. The
+	// annotations the operator sets itself, like the config hash, take precedence.
+	AdditionalPodAnnotations map[string]string `json:"additionalPodAnnotations,omitempty"`
+
 	// Cassandra users to bootstrap
 	Users []CassandraUser `json:"users,omitempty"`
 
@@ -196,6 +418,95 @@ type CassandraDatacenterSpec struct {
 
 	AdditionalSeeds []string `json:"additionalSeeds,omitempty"`
 
+	// Minimum number of nodes each rack must keep when scaling down. Size changes which would
+	// leave a rack with fewer nodes are rejected. Not checked when not set.
+	// +kubebuilder:validation:Minimum=0
+	MinNodesPerRack int32 `json:"minNodesPerRack,omitempty"`
+
+	// Number of pods labelled as seeds in each rack, capped at the rack's node count. When
+	// not set, the datacenter has three seeds spread over the racks, or one per rack if
+	// there are more than three racks.
+	// +kubebuilder:validation:Minimum=1
+	SeedsPerRack int32 `json:"seedsPerRack,omitempty"`
+
+	// Turning this option on rejects racks pinned to the same zone, through zone or the
+	// zone label of the node affinity labels. Otherwise such racks are only logged as a
+	// warning, as they weaken the fault isolation racks are meant to give.
+	RequireDistinctRackZones bool `json:"requireDistinctRackZones,omitempty"`
+
+	// Which racks get the extra nodes when Size is not a multiple of the number of racks.
+	// FirstRacks, the default, gives them to the first racks in the list, LastRacks to the
+	// last ones, and PreferredRacks to the racks with preferExtraNodes set first, then to
+	// the others in list order.
+	// +kubebuilder:validation:Enum=FirstRacks;LastRacks;PreferredRacks
+	RackDistributionStrategy string `json:"rackDistributionStrategy,omitempty"`
+
+	// Sources of additional env vars for the server container, e.g. a ConfigMap holding JVM
+	// tuning settings. They may not define the env vars the operator sets itself.
+	EnvFrom []corev1.EnvFromSource `json:"envFrom,omitempty"`
+
+	// Turning this option on removes the preStop hook which drains the node through the
+	// management API before the cassandra container is stopped. Without the drain, the
+	// commitlog has to be replayed when the node starts again.
+	DisablePreStopDrain bool `json:"disablePreStopDrain,omitempty"`
+
+	// Root log level of the server, rendered as logback-xml root-log-level. A root-log-level
+	// set in Config takes precedence.
+	// +kubebuilder:validation:Enum=ERROR;WARN;INFO;DEBUG;TRACE
+	LogLevel string `json:"logLevel,omitempty"`
+
+	// Sets prefer_local in cassandra-rackdc.properties, which makes GossipingPropertyFileSnitch
+	// connect to nodes of the same datacenter through their local address. A prefer_local
+	// set in Config takes precedence.
+	PreferLocal bool `json:"preferLocal,omitempty"`
+
+	// Sets dc_suffix in cassandra-rackdc.properties, which GossipingPropertyFileSnitch
+	// appends to the datacenter name the server reports. Keyspace replication has to use
+	// the suffixed name. A dc_suffix set in Config takes precedence. Cannot be changed
+	// after the datacenter is created.
+	DcSuffix string `json:"dcSuffix,omitempty"`
+
+	// Turning this option on runs a keyspace cleanup through the management API on every
+	// started node, one node at a time, once a scale up has completed. This reclaims the
+	// data the existing nodes no longer own, at the cost of compaction load.
+	AutoCleanupAfterScaleUp bool `json:"autoCleanupAfterScaleUp,omitempty"`
+
+	// Hinted handoff, batchlog and commitlog sync settings of cassandra.yaml. Values set in
+	// Config take precedence.
+	Durability *DurabilityConfig `json:"durability,omitempty"`
+
+	// Request timeout settings of cassandra.yaml. Values set in Config take precedence.
+	RequestTimeouts *RequestTimeoutsConfig `json:"requestTimeouts,omitempty"`
+
+	// Memtable and cache size settings of cassandra.yaml. Values set in Config take
+	// precedence.
+	MemtablesAndCaches *MemtablesAndCachesConfig `json:"memtablesAndCaches,omitempty"`
+
+	// Turning this option on enables the prometheus collectd writer, rendered as
+	// 10-write-prom-conf enabled. Values set in Config take precedence.
+	EnablePrometheus bool `json:"enablePrometheus,omitempty"`
+
+	// Port of the prometheus collectd writer, rendered as 10-write-prom-conf port. Defaults
+	// to 9103. Values set in Config take precedence.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	PrometheusPort int `json:"prometheusPort,omitempty"`
+
+	// Turning this option on makes the JVM write a rotated GC log, rendered as the
+	// additional-jvm-opts of the jvm options sections of the server version.
+	EnableGCLogging bool `json:"enableGCLogging,omitempty"`
+
+	// Absolute path of the GC log written when EnableGCLogging is set. It has to be below
+	// the server logs directory, the data directory or the mount path of an additional
+	// volume. Defaults to /var/log/cassandra/gc.log.
+	GCLogPath string `json:"gcLogPath,omitempty"`
+
+	// Interval in seconds after which the datacenter is reconciled again once it is fully
+	// reconciled. When not set, it is only reconciled again when it or the resources it
+	// owns change.
+	// +kubebuilder:validation:Minimum=1
+	ReconcileIntervalSeconds int32 `json:"reconcileIntervalSeconds,omitempty"`
+
 	// Deprecated: Reaper's sidecar mode has too many problems in Kubernetes for it to
 	// usable. In order for it to work reliably, changes in Reaper would be needed. See
 	// https://github.com/thelastpickle/cassandra-reaper/issues/956 for details. Because
@@ -213,6 +524,40 @@ type CassandraDatacenterSpec struct {
 	// Avoid label "cass-operator" and anything that starts with "cassandra.datastax.com/"
 	AdditionalServiceConfig ServiceConfig `json:"additionalServiceConfig,omitempty"`
 
+	// Whether the all-pods service publishes the addresses of pods that are not ready. Defaults
+	// to true. Pods are not ready while Cassandra bootstraps, so disabling this can prevent the
+	// pods of a new cluster from resolving each other and break initial cluster formation.
+	AllPodsServicePublishNotReadyAddresses *bool `json:"allPodsServicePublishNotReadyAddresses,omitempty"`
+
+	// Turning this option on creates a service load balancing over the management API of the
+	// ready server pods, for tools that need a stable endpoint rather than the pod DNS names.
+	// Its port uses https when the management API is secured with TLS.
+	ManagementApiService bool `json:"managementApiService,omitempty"`
+
+	// Where the management API runs. InContainer, the default, expects it to be baked into
+	// the server image. Sidecar runs it in a separate management-api container from
+	// ManagementApiImage, which the probes, the preStop drain and the management API port
+	// and TLS settings then target. Volumes and env vars the sidecar needs to reach the
+	// server can be added to a management-api container in PodTemplateSpec.
+	// +kubebuilder:validation:Enum=InContainer;Sidecar
+	ManagementApiMode string `json:"managementApiMode,omitempty"`
+
+	// Container image of the management API sidecar, required with ManagementApiMode Sidecar
+	ManagementApiImage string `json:"managementApiImage,omitempty"`
+
+	// Turning this option on makes the seed service always publish the addresses of seeds
+	// that are not ready. Otherwise it only does so while the operator is working on the
+	// datacenter, such as during initial cluster formation, and publishes only ready seeds
+	// once the datacenter is ready. The seed service is shared by the datacenters of the
+	// cluster, so it publishes not-ready seeds as long as any of them needs it.
+	AlwaysPublishNotReadySeeds bool `json:"alwaysPublishNotReadySeeds,omitempty"`
+
+	// Desired replication factor of the system_auth keyspace per datacenter name. Cassandra
+	// replaces the whole replication map of a keyspace when altering it, so every datacenter
+	// of the cluster should be listed, including this one. When set, the operator alters the
+	// keyspace after upserting the users.
+	SystemAuthReplication map[string]int32 `json:"systemAuthReplication,omitempty"`
+
 	// Tolerations applied to the Cassandra pod. Note that these cannot be overridden with PodTemplateSpec.
 	Tolerations []corev1.Toleration `json:"tolerations,omitempty"`
 }
@@ -220,6 +565,18 @@ type CassandraDatacenterSpec struct {
 type NetworkingConfig struct {
 	NodePort    *NodePortConfig `json:"nodePort,omitempty"`
 	HostNetwork bool            `json:"hostNetwork,omitempty"`
+
+	// Network interface, e.g. "bond0", the server listens on for internode traffic,
+	// rendered as listen_interface instead of listening on an address. This is meant for
+	// bare-metal workers with several network interfaces, usually with hostNetwork. An
+	// init container then deletes the listen_address the config builder renders from the
+	// pod address, and it cannot be combined with a listen_address set in Config. Values
+	// set in Config take precedence.
+	ListenInterface string `json:"listenInterface,omitempty"`
+
+	// Turning this option on makes the server listen on the IPv6 address of
+	// ListenInterface when it has both, rendered as listen_interface_prefer_ipv6.
+	ListenInterfacePreferIPv6 bool `json:"listenInterfacePreferIPv6,omitempty"`
 }
 
 type NodePortConfig struct {
@@ -239,6 +596,325 @@ func (dc *CassandraDatacenter) IsHostNetworkEnabled() bool {
 	return networking != nil && networking.HostNetwork
 }
 
+// IsListenInterfaceEnabled returns whether the server listens on the network interface
+// ListenInterface instead of the pod address
+func (dc *CassandraDatacenter) IsListenInterfaceEnabled() bool {
+	networking := dc.Spec.Networking
+	return networking != nil && networking.ListenInterface != ""
+}
+
+// DurabilityConfig holds cassandra.yaml settings on how writes reach their replicas and
+// their disks
+type DurabilityConfig struct {
+	// Rendered as hinted_handoff_enabled
+	HintedHandoffEnabled *bool `json:"hintedHandoffEnabled,omitempty"`
+
+	// Duration, e.g. "3h", after which no more hints are stored for an unreachable node.
+	// Rendered as max_hint_window_in_ms.
+	MaxHintWindow string `json:"maxHintWindow,omitempty"`
+
+	// Quantity, e.g. "1Mi", of hints delivered per second by each node. Rendered as
+	// hinted_handoff_throttle_in_kb.
+	HintedHandoffThrottle string `json:"hintedHandoffThrottle,omitempty"`
+
+	// Quantity, e.g. "1Mi", of batchlog replayed per second by each node. Rendered as
+	// batchlog_replay_throttle_in_kb.
+	BatchlogReplayThrottle string `json:"batchlogReplayThrottle,omitempty"`
+
+	// Whether writes are acknowledged once the commitlog is synced, batch, once the
+	// commitlog is synced together with the writes of commitlogSyncGroupWindow, group, or
+	// the commitlog is synced every commitlogSyncPeriod, periodic. Rendered as
+	// commitlog_sync. The group commitlogSync requires Cassandra 4.0.
+	// +kubebuilder:validation:Enum=periodic;batch;group
+	CommitlogSync string `json:"commitlogSync,omitempty"`
+
+	// Duration, e.g. "10s", between commitlog syncs, required with the periodic
+	// commitlogSync. Rendered as commitlog_sync_period_in_ms.
+	CommitlogSyncPeriod string `json:"commitlogSyncPeriod,omitempty"`
+
+	// Duration, e.g. "15ms", between commitlog syncs, required with the group
+	// commitlogSync. Rendered as commitlog_sync_group_window_in_ms.
+	CommitlogSyncGroupWindow string `json:"commitlogSyncGroupWindow,omitempty"`
+}
+
+// GetDurabilityConfigValues returns the cassandra.yaml settings of Durability, failing when
+// a duration or quantity cannot be parsed or the commitlog sync settings do not match
+func (dc *CassandraDatacenter) GetDurabilityConfigValues() (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	durability := dc.Spec.Durability
+	if durability == nil {
+		return values, nil
+	}
+
+	if durability.HintedHandoffEnabled != nil {
+		values["hinted_handoff_enabled"] = *durability.HintedHandoffEnabled
+	}
+
+	if durability.MaxHintWindow != "" {
+		window, err := time.ParseDuration(durability.MaxHintWindow)
+		if err != nil || window < 0 {
+			return nil, fmt.Errorf("maxHintWindow '%s' is not a duration", durability.MaxHintWindow)
+		}
+		values["max_hint_window_in_ms"] = window.Milliseconds()
+	}
+
+	switch durability.CommitlogSync {
+	case "":
+	case "periodic", "batch", "group":
+		values["commitlog_sync"] = durability.CommitlogSync
+	default:
+		return nil, fmt.Errorf("commitlogSync '%s' is neither periodic, batch nor group", durability.CommitlogSync)
+	}
+
+	if durability.CommitlogSyncPeriod != "" {
+		if durability.CommitlogSync == "batch" || durability.CommitlogSync == "group" {
+			return nil, fmt.Errorf("commitlogSyncPeriod only applies to the periodic commitlogSync")
+		}
+		period, err := time.ParseDuration(durability.CommitlogSyncPeriod)
+		if err != nil || period <= 0 {
+			return nil, fmt.Errorf("commitlogSyncPeriod '%s' is not a positive duration", durability.CommitlogSyncPeriod)
+		}
+		values["commitlog_sync_period_in_ms"] = period.Milliseconds()
+	} else if durability.CommitlogSync == "periodic" {
+		return nil, fmt.Errorf("commitlogSyncPeriod is required with the periodic commitlogSync")
+	}
+
+	if durability.CommitlogSyncGroupWindow != "" {
+		if durability.CommitlogSync != "group" {
+			return nil, fmt.Errorf("commitlogSyncGroupWindow only applies to the group commitlogSync")
+		}
+		window, err := time.ParseDuration(durability.CommitlogSyncGroupWindow)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("commitlogSyncGroupWindow '%s' is not a positive duration", durability.CommitlogSyncGroupWindow)
+		}
+		values["commitlog_sync_group_window_in_ms"] = window.Milliseconds()
+	} else if durability.CommitlogSync == "group" {
+		return nil, fmt.Errorf("commitlogSyncGroupWindow is required with the group commitlogSync")
+	}
+
+	throttles := []struct {
+		field string
+		value string
+		key   string
+	}{
+		{"hintedHandoffThrottle", durability.HintedHandoffThrottle, "hinted_handoff_throttle_in_kb"},
+		{"batchlogReplayThrottle", durability.BatchlogReplayThrottle, "batchlog_replay_throttle_in_kb"},
+	}
+	for _, throttle := range throttles {
+		if throttle.value == "" {
+			continue
+		}
+		quantity, err := resource.ParseQuantity(throttle.value)
+		if err != nil || quantity.Sign() < 0 {
+			return nil, fmt.Errorf("%s '%s' is not a quantity", throttle.field, throttle.value)
+		}
+		values[throttle.key] = quantity.Value() / 1024
+	}
+
+	return values, nil
+}
+
+// RequestTimeoutsConfig holds the cassandra.yaml timeouts, in milliseconds, coordinators
+// wait for replicas to answer a request
+type RequestTimeoutsConfig struct {
+	// Rendered as read_request_timeout_in_ms
+	// +kubebuilder:validation:Minimum=1
+	ReadMs int64 `json:"readMs,omitempty"`
+
+	// Rendered as range_request_timeout_in_ms
+	// +kubebuilder:validation:Minimum=1
+	RangeMs int64 `json:"rangeMs,omitempty"`
+
+	// Rendered as write_request_timeout_in_ms
+	// +kubebuilder:validation:Minimum=1
+	WriteMs int64 `json:"writeMs,omitempty"`
+
+	// Rendered as counter_write_request_timeout_in_ms
+	// +kubebuilder:validation:Minimum=1
+	CounterWriteMs int64 `json:"counterWriteMs,omitempty"`
+
+	// Rendered as cas_contention_timeout_in_ms
+	// +kubebuilder:validation:Minimum=1
+	CasContentionMs int64 `json:"casContentionMs,omitempty"`
+
+	// Rendered as truncate_request_timeout_in_ms
+	// +kubebuilder:validation:Minimum=1
+	TruncateMs int64 `json:"truncateMs,omitempty"`
+
+	// Default for other requests, rendered as request_timeout_in_ms
+	// +kubebuilder:validation:Minimum=1
+	OtherMs int64 `json:"otherMs,omitempty"`
+}
+
+// GetRequestTimeoutConfigValues returns the cassandra.yaml settings of RequestTimeouts,
+// failing when a timeout is not positive
+func (dc *CassandraDatacenter) GetRequestTimeoutConfigValues() (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	timeouts := dc.Spec.RequestTimeouts
+	if timeouts == nil {
+		return values, nil
+	}
+
+	settings := []struct {
+		field string
+		value int64
+		key   string
+	}{
+		{"readMs", timeouts.ReadMs, "read_request_timeout_in_ms"},
+		{"rangeMs", timeouts.RangeMs, "range_request_timeout_in_ms"},
+		{"writeMs", timeouts.WriteMs, "write_request_timeout_in_ms"},
+		{"counterWriteMs", timeouts.CounterWriteMs, "counter_write_request_timeout_in_ms"},
+		{"casContentionMs", timeouts.CasContentionMs, "cas_contention_timeout_in_ms"},
+		{"truncateMs", timeouts.TruncateMs, "truncate_request_timeout_in_ms"},
+		{"otherMs", timeouts.OtherMs, "request_timeout_in_ms"},
+	}
+	for _, setting := range settings {
+		if setting.value == 0 {
+			continue
+		}
+		if setting.value < 0 {
+			return nil, fmt.Errorf("%s %d is not a positive number of milliseconds", setting.field, setting.value)
+		}
+		values[setting.key] = setting.value
+	}
+
+	return values, nil
+}
+
+// MemtablesAndCachesConfig holds the cassandra.yaml memory sizes of memtables and caches, in
+// megabytes. Settings which are not set keep the server default, which Cassandra derives
+// from the heap size for most of them. Zero disables a cache.
+type MemtablesAndCachesConfig struct {
+	// Rendered as memtable_heap_space_in_mb
+	// +kubebuilder:validation:Minimum=0
+	MemtableHeapSpaceMb *int64 `json:"memtableHeapSpaceMb,omitempty"`
+
+	// Rendered as memtable_offheap_space_in_mb
+	// +kubebuilder:validation:Minimum=0
+	MemtableOffheapSpaceMb *int64 `json:"memtableOffheapSpaceMb,omitempty"`
+
+	// Rendered as key_cache_size_in_mb
+	// +kubebuilder:validation:Minimum=0
+	KeyCacheSizeMb *int64 `json:"keyCacheSizeMb,omitempty"`
+
+	// Rendered as row_cache_size_in_mb
+	// +kubebuilder:validation:Minimum=0
+	RowCacheSizeMb *int64 `json:"rowCacheSizeMb,omitempty"`
+
+	// Rendered as counter_cache_size_in_mb
+	// +kubebuilder:validation:Minimum=0
+	CounterCacheSizeMb *int64 `json:"counterCacheSizeMb,omitempty"`
+
+	// Rendered as file_cache_size_in_mb
+	// +kubebuilder:validation:Minimum=0
+	FileCacheSizeMb *int64 `json:"fileCacheSizeMb,omitempty"`
+}
+
+// GetListenInterfaceConfigValues returns the cassandra.yaml settings of the listen
+// interface of Networking, failing when the interface name is not a valid network
+// interface name or the IPv6 preference is set without an interface
+func (dc *CassandraDatacenter) GetListenInterfaceConfigValues() (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	networking := dc.Spec.Networking
+	if networking == nil {
+		return values, nil
+	}
+
+	if networking.ListenInterface == "" {
+		if networking.ListenInterfacePreferIPv6 {
+			return nil, fmt.Errorf("listenInterfacePreferIPv6 requires listenInterface")
+		}
+		return values, nil
+	}
+
+	// Linux limits interface names to 15 bytes
+	name := networking.ListenInterface
+	if len(name) > 15 || strings.ContainsAny(name, "/ \t\n") {
+		return nil, fmt.Errorf("listenInterface '%s' is not a network interface name", name)
+	}
+	values["listen_interface"] = name
+	if networking.ListenInterfacePreferIPv6 {
+		values["listen_interface_prefer_ipv6"] = true
+	}
+
+	return values, nil
+}
+
+// GetGCLogPath returns the path of the GC log written when EnableGCLogging is set
+func (dc *CassandraDatacenter) GetGCLogPath() string {
+	if dc.Spec.GCLogPath != "" {
+		return path.Clean(dc.Spec.GCLogPath)
+	}
+	return DefaultGCLogPath
+}
+
+// GetGCLoggingJvmOptions returns the GC logging JVM flags of EnableGCLogging by the jvm
+// options section they are rendered in, or nil when GC logging is off. Cassandra 3.x
+// only runs on Java 8, other servers get the flags of both Java 8 and Java 11 in their
+// JVM specific sections as the unified logging of Java 11 rejects the Java 8 flags.
+func (dc *CassandraDatacenter) GetGCLoggingJvmOptions() map[string][]string {
+	if !dc.Spec.EnableGCLogging {
+		return nil
+	}
+
+	logPath := dc.GetGCLogPath()
+	java8Options := []string{
+		"-Xloggc:" + logPath,
+		"-XX:+PrintGCDetails",
+		"-XX:+PrintGCDateStamps",
+		"-XX:+PrintGCApplicationStoppedTime",
+		"-XX:+UseGCLogFileRotation",
+		"-XX:NumberOfGCLogFiles=10",
+		"-XX:GCLogFileSize=10M",
+	}
+	if dc.Spec.ServerType == "cassandra" && strings.HasPrefix(dc.Spec.ServerVersion, "3.") {
+		return map[string][]string{"jvm-options": java8Options}
+	}
+
+	java11Options := []string{
+		"-Xlog:gc=info,heap*=trace,age*=debug,safepoint=info,promotion*=trace:file=" + logPath +
+			":time,uptime,pid,tid,level:filecount=10,filesize=10485760",
+	}
+	return map[string][]string{
+		"jvm8-server-options":  java8Options,
+		"jvm11-server-options": java11Options,
+	}
+}
+
+// GetMemtablesAndCachesConfigValues returns the cassandra.yaml settings of
+// MemtablesAndCaches, failing when a size is negative
+func (dc *CassandraDatacenter) GetMemtablesAndCachesConfigValues() (map[string]interface{}, error) {
+	values := map[string]interface{}{}
+	sizes := dc.Spec.MemtablesAndCaches
+	if sizes == nil {
+		return values, nil
+	}
+
+	settings := []struct {
+		field string
+		value *int64
+		key   string
+	}{
+		{"memtableHeapSpaceMb", sizes.MemtableHeapSpaceMb, "memtable_heap_space_in_mb"},
+		{"memtableOffheapSpaceMb", sizes.MemtableOffheapSpaceMb, "memtable_offheap_space_in_mb"},
+		{"keyCacheSizeMb", sizes.KeyCacheSizeMb, "key_cache_size_in_mb"},
+		{"rowCacheSizeMb", sizes.RowCacheSizeMb, "row_cache_size_in_mb"},
+		{"counterCacheSizeMb", sizes.CounterCacheSizeMb, "counter_cache_size_in_mb"},
+		{"fileCacheSizeMb", sizes.FileCacheSizeMb, "file_cache_size_in_mb"},
+	}
+	for _, setting := range settings {
+		if setting.value == nil {
+			continue
+		}
+		if *setting.value < 0 {
+			return nil, fmt.Errorf("%s %d is negative", setting.field, *setting.value)
+		}
+		values[setting.key] = *setting.value
+	}
+
+	return values, nil
+}
+
 type DseWorkloads struct {
 	AnalyticsEnabled bool `json:"analyticsEnabled,omitempty"`
 	GraphEnabled     bool `json:"graphEnabled,omitempty"`
@@ -261,6 +937,27 @@ type AdditionalVolumesSlice []AdditionalVolumes
 type StorageConfig struct {
 	CassandraDataVolumeClaimSpec *corev1.PersistentVolumeClaimSpec `json:"cassandraDataVolumeClaimSpec,omitempty"`
 	AdditionalVolumes            AdditionalVolumesSlice            `json:"additionalVolumes,omitempty"`
+
+	// Absolute path where the server data volume is mounted in the cassandra container.
+	// The data, commitlog, hints and saved caches directories of cassandra.yaml are
+	// placed under it. Defaults to /var/lib/cassandra.
+	DataDirectory string `json:"dataDirectory,omitempty"`
+}
+
+// GetDataDirectory returns the path where the server data volume is mounted
+func (dc *CassandraDatacenter) GetDataDirectory() string {
+	if dc.Spec.StorageConfig.DataDirectory != "" {
+		return path.Clean(dc.Spec.StorageConfig.DataDirectory)
+	}
+	return DefaultDataDirectory
+}
+
+// GetConfigMountPath returns the path where the rendered server config is mounted
+func (dc *CassandraDatacenter) GetConfigMountPath() string {
+	if dc.Spec.ConfigMountPath != "" {
+		return path.Clean(dc.Spec.ConfigMountPath)
+	}
+	return DefaultConfigMountPath
 }
 
 // GetRacks is a getter for the Rack slice in the spec
@@ -275,6 +972,77 @@ func (dc *CassandraDatacenter) GetRacks() []Rack {
 	}}
 }
 
+// HasRack returns whether the datacenter has a rack of the given name
+func (dc *CassandraDatacenter) HasRack(rackName string) bool {
+	for _, rack := range dc.GetRacks() {
+		if rack.Name == rackName {
+			return true
+		}
+	}
+	return false
+}
+
+// GetAntiAffinityTopologyKey returns the topology key of the podAntiAffinity between
+// server pods
+func (dc *CassandraDatacenter) GetAntiAffinityTopologyKey() string {
+	if dc.Spec.AntiAffinityTopologyKey != "" {
+		return dc.Spec.AntiAffinityTopologyKey
+	}
+	return corev1.LabelHostname
+}
+
+// GetAntiAffinityLabels returns the labels selecting the server pods the podAntiAffinity
+// keeps the server pods of the datacenter apart from: the cluster labels with the Cluster
+// scope, the datacenter labels with the Datacenter scope, and nil when AntiAffinityScope
+// is unset, in which case every server pod is selected
+func (dc *CassandraDatacenter) GetAntiAffinityLabels() map[string]string {
+	switch dc.Spec.AntiAffinityScope {
+	case AntiAffinityScopeCluster:
+		return dc.GetClusterLabels()
+	case AntiAffinityScopeDatacenter:
+		return dc.GetDatacenterLabels()
+	}
+	return nil
+}
+
+// GetOperatorFinalizers returns the finalizers the operator manages on a CassandraDatacenter
+func GetOperatorFinalizers() []string {
+	return []string{Finalizer}
+}
+
+// HasOperatorFinalizer returns whether the operator's finalizer is set on the datacenter
+func (dc *CassandraDatacenter) HasOperatorFinalizer() bool {
+	for _, finalizer := range dc.GetFinalizers() {
+		if finalizer == Finalizer {
+			return true
+		}
+	}
+	return false
+}
+
+// GetRemovedRacks returns the names of the racks of oldDc which are no longer in the
+// racks of the datacenter
+func (dc *CassandraDatacenter) GetRemovedRacks(oldDc *CassandraDatacenter) []string {
+	removed := []string{}
+	for _, rack := range oldDc.GetRacks() {
+		if !dc.HasRack(rack.Name) {
+			removed = append(removed, rack.Name)
+		}
+	}
+	return removed
+}
+
+// GetRackResources returns the resources of the server containers in the given rack. A
+// rack's own resources take precedence over the datacenter level resources.
+func (dc *CassandraDatacenter) GetRackResources(rackName string) corev1.ResourceRequirements {
+	for _, rack := range dc.GetRacks() {
+		if rack.Name == rackName && rack.Resources != nil {
+			return *rack.Resources
+		}
+	}
+	return dc.Spec.Resources
+}
+
 // ServiceConfig defines additional service configurations.
 type ServiceConfig struct {
 	DatacenterService     ServiceConfigAdditions `json:"dcService,omitempty"`
@@ -301,6 +1069,14 @@ type Rack struct {
 
 	//NodeAffinityLabels to pin the rack, using node affinity
 	NodeAffinityLabels map[string]string `json:"nodeAffinityLabels,omitempty"`
+
+	// Kubernetes resource requests and limits for the server containers of this rack,
+	// overriding the datacenter level resources
+	Resources *corev1.ResourceRequirements `json:"resources,omitempty"`
+
+	// Whether the rack gets the extra nodes first with the PreferredRacks
+	// rackDistributionStrategy
+	PreferExtraNodes bool `json:"preferExtraNodes,omitempty"`
 }
 
 type CassandraNodeStatus struct {
@@ -450,6 +1226,31 @@ func (dc *CassandraDatacenter) GetConfigBuilderImage() string {
 	return dc.Spec.ConfigBuilderImage
 }
 
+// GetConfigBuilderImagePullPolicy returns the pull policy of the config builder container.
+// The operator's default image is pinned by tag, so it defaults to IfNotPresent.
+func (dc *CassandraDatacenter) GetConfigBuilderImagePullPolicy() corev1.PullPolicy {
+	if dc.Spec.ConfigBuilderImagePullPolicy != "" {
+		return dc.Spec.ConfigBuilderImagePullPolicy
+	}
+	if dc.Spec.ConfigBuilderImage == "" {
+		return corev1.PullIfNotPresent
+	}
+	return images.DefaultPullPolicy(dc.Spec.ConfigBuilderImage)
+}
+
+// GetServerImagePullPolicy returns the pull policy of the cassandra container. The
+// operator's default images for the server version are pinned by tag, so it defaults to
+// IfNotPresent.
+func (dc *CassandraDatacenter) GetServerImagePullPolicy() corev1.PullPolicy {
+	if dc.Spec.ServerImagePullPolicy != "" {
+		return dc.Spec.ServerImagePullPolicy
+	}
+	if dc.Spec.ServerImage == "" {
+		return corev1.PullIfNotPresent
+	}
+	return images.DefaultPullPolicy(dc.Spec.ServerImage)
+}
+
 // GetServerImage produces a fully qualified container image to pull
 // based on either the version, or an explicitly specified image
 //
@@ -459,6 +1260,18 @@ func (dc *CassandraDatacenter) GetServerImage() string {
 	return dc.Spec.ServerImage
 }
 
+// GetServerImageBestEffort returns the image of the server container, from ServerImage or
+// resolved from the server type and version like the controller does, and whether it
+// could be resolved. Instead of failing for server versions the controller rejects, it
+// returns the image name the version would have, or an empty name, and false, so that
+// partial specs can still be previewed.
+func (dc *CassandraDatacenter) GetServerImageBestEffort() (string, bool) {
+	if dc.GetServerImage() != "" {
+		return dc.GetServerImage(), true
+	}
+	return images.GetCassandraImageBestEffort(dc.Spec.ServerType, dc.Spec.ServerVersion)
+}
+
 // GetRackLabels ...
 func (dc *CassandraDatacenter) GetRackLabels(rackName string) map[string]string {
 	labels := dc.GetDatacenterLabels()
@@ -466,6 +1279,34 @@ func (dc *CassandraDatacenter) GetRackLabels(rackName string) map[string]string
 	return labels
 }
 
+// GetPodLabelsDelta returns the labels to add or update on a server pod of the given rack
+// with the current labels, so that it has the labels of the pod template: the labels of
+// PodTemplateSpec and the operator's rack labels, which take precedence. Labels are never
+// removed, as the pod carries labels set by others and its node state label.
+func (dc *CassandraDatacenter) GetPodLabelsDelta(rackName string, currentLabels map[string]string) map[string]string {
+	desired := map[string]string{}
+	if dc.Spec.PodTemplateSpec != nil {
+		for key, value := range dc.Spec.PodTemplateSpec.Labels {
+			desired[key] = value
+		}
+	}
+	operatorLabels := dc.GetRackLabels(rackName)
+	oplabels.AddManagedByLabel(operatorLabels)
+	for key, value := range operatorLabels {
+		desired[key] = value
+	}
+	// The operator moves the node state label through the server lifecycle
+	delete(desired, CassNodeState)
+
+	delta := map[string]string{}
+	for key, value := range desired {
+		if current, ok := currentLabels[key]; !ok || current != value {
+			delta[key] = value
+		}
+	}
+	return delta
+}
+
 func (status *CassandraDatacenterStatus) GetConditionStatus(conditionType DatacenterConditionType) corev1.ConditionStatus {
 	for _, condition := range status.Conditions {
 		if condition.Type == conditionType {
@@ -479,6 +1320,34 @@ func (dc *CassandraDatacenter) GetConditionStatus(conditionType DatacenterCondit
 	return (&dc.Status).GetConditionStatus(conditionType)
 }
 
+// PodNeedsRollingRestart returns whether the pod was created before the last requested
+// rolling restart, and so still has to be restarted
+func (dc *CassandraDatacenter) PodNeedsRollingRestart(pod *corev1.Pod) bool {
+	podStartTime := pod.GetCreationTimestamp()
+	return podStartTime.Before(&dc.Status.LastRollingRestart)
+}
+
+// IsRollingRestartInProgress returns whether a rolling restart has been requested and not
+// yet completed, given the pods of the datacenter. This is the case from when
+// RollingRestartRequested is set, while any pod still needs to be restarted, and until the
+// operator clears the RollingRestart condition.
+func (dc *CassandraDatacenter) IsRollingRestartInProgress(pods []*corev1.Pod) bool {
+	if dc.Spec.RollingRestartRequested {
+		return true
+	}
+
+	if dc.GetConditionStatus(DatacenterRollingRestart) == corev1.ConditionTrue {
+		return true
+	}
+
+	for _, pod := range pods {
+		if dc.PodNeedsRollingRestart(pod) {
+			return true
+		}
+	}
+	return false
+}
+
 func (dc *CassandraDatacenter) GetCondition(conditionType DatacenterConditionType) (DatacenterCondition, bool) {
 	for _, condition := range dc.Status.Conditions {
 		if condition.Type == conditionType {
@@ -510,6 +1379,34 @@ func (dc *CassandraDatacenter) SetCondition(condition DatacenterCondition) {
 	(&dc.Status).SetCondition(condition)
 }
 
+// GetStaleNodeStatuses returns the sorted NodeStatuses keys that do not belong to any of
+// the given pod names, e.g. entries left behind for pods removed by a scale down.
+func (status *CassandraDatacenterStatus) GetStaleNodeStatuses(podNames []string) []string {
+	expected := make(map[string]bool, len(podNames))
+	for _, podName := range podNames {
+		expected[podName] = true
+	}
+
+	stale := []string{}
+	for podName := range status.NodeStatuses {
+		if !expected[podName] {
+			stale = append(stale, podName)
+		}
+	}
+	sort.Strings(stale)
+	return stale
+}
+
+// PruneNodeStatuses removes the NodeStatuses entries that do not belong to any of the
+// given pod names and returns the removed keys.
+func (status *CassandraDatacenterStatus) PruneNodeStatuses(podNames []string) []string {
+	stale := status.GetStaleNodeStatuses(podNames)
+	for _, podName := range stale {
+		delete(status.NodeStatuses, podName)
+	}
+	return stale
+}
+
 // GetDatacenterLabels ...
 func (dc *CassandraDatacenter) GetDatacenterLabels() map[string]string {
 	labels := dc.GetClusterLabels()
@@ -524,6 +1421,13 @@ func (dc *CassandraDatacenter) GetClusterLabels() map[string]string {
 	}
 }
 
+// IsSameCluster returns whether the other datacenter belongs to the same cluster, which is
+// the case when it has the same cluster labels in the same namespace. Datacenters in other
+// namespaces do not join the cluster, as they do not share the seed service.
+func (dc *CassandraDatacenter) IsSameCluster(other *CassandraDatacenter) bool {
+	return dc.Namespace == other.Namespace && reflect.DeepEqual(dc.GetClusterLabels(), other.GetClusterLabels())
+}
+
 func (dc *CassandraDatacenter) GetSeedServiceName() string {
 	return dc.Spec.ClusterName + "-seed-service"
 }
@@ -536,6 +1440,95 @@ func (dc *CassandraDatacenter) GetAllPodsServiceName() string {
 	return dc.Spec.ClusterName + "-" + dc.Name + "-all-pods-service"
 }
 
+// GetManagedSeedServiceOverlaps returns the AdditionalSeeds entries which address the
+// seed service or the all-pods service of the datacenter, by name or by a DNS name in the
+// namespace of the datacenter, by the name of the service they address. The seed list
+// already includes the seed service, and the all-pods service would make every node a seed.
+func (dc *CassandraDatacenter) GetManagedSeedServiceOverlaps() map[string]string {
+	overlaps := map[string]string{}
+	for _, seed := range dc.Spec.AdditionalSeeds {
+		address := strings.TrimSuffix(seed, ".")
+		for _, service := range []string{dc.GetSeedServiceName(), dc.GetAllPodsServiceName()} {
+			qualified := service + "." + dc.Namespace
+			if address == service || address == qualified || address == qualified+".svc" ||
+				strings.HasPrefix(address, qualified+".svc.") {
+				overlaps[seed] = service
+			}
+		}
+	}
+	return overlaps
+}
+
+// GetStatefulSetServiceName returns the name of the headless service governing the
+// statefulsets of the datacenter, which gives the pods their DNS names of the form
+// <pod>.<service>.<namespace>.svc. This is the all-pods service, which the operator always
+// creates. It may not change, as the service name of a statefulset is immutable.
+func (dc *CassandraDatacenter) GetStatefulSetServiceName() string {
+	return dc.GetAllPodsServiceName()
+}
+
+// GetAllPodsServicePublishNotReadyAddresses returns whether the all-pods service should
+// publish not-ready addresses. This defaults to true as seed resolution during bootstrap
+// depends on it.
+func (dc *CassandraDatacenter) GetAllPodsServicePublishNotReadyAddresses() bool {
+	if dc.Spec.AllPodsServicePublishNotReadyAddresses != nil {
+		return *dc.Spec.AllPodsServicePublishNotReadyAddresses
+	}
+	return true
+}
+
+// GetSeedServicePublishNotReadyAddresses returns whether the datacenter needs the seed
+// service to publish not-ready addresses. Seeds are not ready while Cassandra bootstraps,
+// so this is the case until the operator has brought the datacenter to Ready.
+func (dc *CassandraDatacenter) GetSeedServicePublishNotReadyAddresses() bool {
+	return dc.Spec.AlwaysPublishNotReadySeeds || dc.Status.CassandraOperatorProgress != ProgressReady
+}
+
+// GetSeedNodeLabels returns the labels selecting the seed nodes of the cluster, which the
+// seed service selects
+func (dc *CassandraDatacenter) GetSeedNodeLabels() map[string]string {
+	labels := dc.GetClusterLabels()
+	labels[SeedNodeLabel] = "true"
+	return labels
+}
+
+// NewSeedService returns the headless seed service of the cluster as the operator maintains
+// it, given whether it publishes not-ready addresses, see
+// GetSeedServicePublishNotReadyAddresses. It selects the seed nodes of all datacenters of
+// the cluster and declares no ports, as it only serves the DNS records of the seeds the
+// config lists. The operator additionally sets a hash annotation on it.
+func (dc *CassandraDatacenter) NewSeedService(publishNotReadyAddresses bool) *corev1.Service {
+	labels := dc.GetClusterLabels()
+	oplabels.AddManagedByLabel(labels)
+
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      dc.GetSeedServiceName(),
+			Namespace: dc.Namespace,
+			Labels:    labels,
+		},
+		Spec: corev1.ServiceSpec{
+			Selector:                 dc.GetSeedNodeLabels(),
+			Type:                     corev1.ServiceTypeClusterIP,
+			ClusterIP:                corev1.ClusterIPNone,
+			PublishNotReadyAddresses: publishNotReadyAddresses,
+		},
+	}
+
+	additions := dc.Spec.AdditionalServiceConfig.SeedService
+	for k, v := range additions.Labels {
+		service.Labels[k] = v
+	}
+	if len(additions.Annotations) > 0 {
+		service.Annotations = make(map[string]string, len(additions.Annotations))
+		for k, v := range additions.Annotations {
+			service.Annotations[k] = v
+		}
+	}
+
+	return service
+}
+
 func (dc *CassandraDatacenter) GetDatacenterServiceName() string {
 	return dc.Spec.ClusterName + "-" + dc.Name + "-service"
 }
@@ -544,25 +1537,328 @@ func (dc *CassandraDatacenter) GetNodePortServiceName() string {
 	return dc.Spec.ClusterName + "-" + dc.Name + "-node-port-service"
 }
 
-func (dc *CassandraDatacenter) ShouldGenerateSuperuserSecret() bool {
-	return len(dc.Spec.SuperuserSecretName) == 0
+func (dc *CassandraDatacenter) GetManagementApiServiceName() string {
+	return dc.Spec.ClusterName + "-" + dc.Name + "-mgmt-api-service"
 }
 
-func (dc *CassandraDatacenter) GetSuperuserSecretNamespacedName() types.NamespacedName {
-	name := dc.Spec.ClusterName + "-superuser"
-	namespace := dc.ObjectMeta.Namespace
-	if len(dc.Spec.SuperuserSecretName) > 0 {
-		name = dc.Spec.SuperuserSecretName
+// GetManagementApiMode returns where the management API runs, ManagementApiModeInContainer
+// unless ManagementApiMode is set
+func (dc *CassandraDatacenter) GetManagementApiMode() string {
+	if dc.Spec.ManagementApiMode == "" {
+		return ManagementApiModeInContainer
 	}
+	return dc.Spec.ManagementApiMode
+}
 
-	return types.NamespacedName{
-		Name:      name,
-		Namespace: namespace,
+// GetManagementApiContainerName returns the name of the pod container running the
+// management API, the server container unless it runs as a sidecar
+func (dc *CassandraDatacenter) GetManagementApiContainerName() string {
+	if dc.GetManagementApiMode() == ManagementApiModeSidecar {
+		return ManagementApiContainerName
 	}
+	return ServerContainerName
 }
 
-// GetConfigAsJSON gets a JSON-encoded string suitable for passing to configBuilder
-func (dc *CassandraDatacenter) GetConfigAsJSON(config []byte) (string, error) {
+// GetStatefulSetName returns the name of the StatefulSet managing the pods of the given rack
+func (dc *CassandraDatacenter) GetStatefulSetName(rackName string) string {
+	return dc.Spec.ClusterName + "-" + dc.Name + "-" + rackName + "-sts"
+}
+
+// ExpectedStatefulSet is a statefulset the operator manages for a rack
+type ExpectedStatefulSet struct {
+	Name      string
+	RackName  string
+	NodeCount int
+}
+
+// GetNodeReplacementStrategy returns how the node of the pod is started, Replace or
+// Restart, when the pod is being replaced, and "" otherwise
+func (dc *CassandraDatacenter) GetNodeReplacementStrategy(podName string) string {
+	replacing := false
+	for _, name := range append(append([]string{}, dc.Spec.ReplaceNodes...), dc.Status.NodeReplacements...) {
+		if name == podName {
+			replacing = true
+			break
+		}
+	}
+	if !replacing {
+		return ""
+	}
+
+	if dc.Spec.NodeReplacementStrategy == NodeReplacementRestart {
+		return NodeReplacementRestart
+	}
+	return NodeReplacementReplace
+}
+
+// GetRackZone returns the zone the rack is pinned to, through its Zone or a zone label of
+// the rack's or the datacenter's node affinity labels, or "" when it is not pinned to one
+func (dc *CassandraDatacenter) GetRackZone(rack Rack) string {
+	if rack.Zone != "" {
+		return rack.Zone
+	}
+	for _, labels := range []map[string]string{rack.NodeAffinityLabels, dc.Spec.NodeAffinityLabels} {
+		for _, key := range []string{corev1.LabelZoneFailureDomain, corev1.LabelZoneFailureDomainStable} {
+			if zone := labels[key]; zone != "" {
+				return zone
+			}
+		}
+	}
+	return ""
+}
+
+// GetRacksSharingZones returns the names of the racks pinned to the same zone by zone, in
+// rack order. Zones with a single rack are left out.
+func (dc *CassandraDatacenter) GetRacksSharingZones() map[string][]string {
+	racksByZone := map[string][]string{}
+	for _, rack := range dc.GetRacks() {
+		if zone := dc.GetRackZone(rack); zone != "" {
+			racksByZone[zone] = append(racksByZone[zone], rack.Name)
+		}
+	}
+
+	for zone, rackNames := range racksByZone {
+		if len(rackNames) < 2 {
+			delete(racksByZone, zone)
+		}
+	}
+	return racksByZone
+}
+
+// GetExpectedStatefulSets returns the statefulsets of the datacenter, one per rack in rack
+// order, with the number of nodes each should have for the datacenter's current size
+func (dc *CassandraDatacenter) GetExpectedStatefulSets() []ExpectedStatefulSet {
+	racks := dc.GetRacks()
+	rackNodeCounts := dc.GetRackNodeCounts(int(dc.Spec.Size))
+
+	statefulSets := []ExpectedStatefulSet{}
+	for idx, rack := range racks {
+		statefulSets = append(statefulSets, ExpectedStatefulSet{
+			Name:      dc.GetStatefulSetName(rack.Name),
+			RackName:  rack.Name,
+			NodeCount: rackNodeCounts[idx],
+		})
+	}
+	return statefulSets
+}
+
+// GetRackSeedCounts returns the number of seeds for each rack, given the number of nodes
+// in each rack
+func (dc *CassandraDatacenter) GetRackSeedCounts(rackNodeCounts []int) []int {
+	if dc.Spec.SeedsPerRack > 0 {
+		seedCounts := make([]int, len(rackNodeCounts))
+		for idx, nodeCount := range rackNodeCounts {
+			seedCounts[idx] = int(dc.Spec.SeedsPerRack)
+			if nodeCount < seedCounts[idx] {
+				seedCounts[idx] = nodeCount
+			}
+		}
+		return seedCounts
+	}
+
+	nodeCount := 0
+	for _, rackNodeCount := range rackNodeCounts {
+		nodeCount += rackNodeCount
+	}
+	rackCount := len(rackNodeCounts)
+
+	// 3 seeds per datacenter (this could be two, but we would like three seeds per cluster
+	// and it's not easy for us to know if we're in a multi DC cluster in this part of the code)
+	// OR all of the nodes, if there's less than 3
+	// OR one per rack if there are four or more racks
+	seedCount := 3
+	if nodeCount < 3 {
+		seedCount = nodeCount
+	} else if rackCount > 3 {
+		seedCount = rackCount
+	}
+
+	return SplitRacks(seedCount, rackCount)
+}
+
+// GetPodNames returns the names of all pods expected for the datacenter's current size,
+// ordered by rack and then by StatefulSet ordinal.
+func (dc *CassandraDatacenter) GetPodNames() []string {
+	podNames := []string{}
+	for _, sts := range dc.GetExpectedStatefulSets() {
+		for ordinal := 0; ordinal < sts.NodeCount; ordinal++ {
+			podNames = append(podNames, fmt.Sprintf("%s-%d", sts.Name, ordinal))
+		}
+	}
+	return podNames
+}
+
+// GetPodNamesByRack returns the names of the pods expected for the datacenter's current
+// size by rack name, ordered by StatefulSet ordinal like GetPodNames
+func (dc *CassandraDatacenter) GetPodNamesByRack() map[string][]string {
+	podNames := map[string][]string{}
+	for _, sts := range dc.GetExpectedStatefulSets() {
+		names := []string{}
+		for ordinal := 0; ordinal < sts.NodeCount; ordinal++ {
+			names = append(names, fmt.Sprintf("%s-%d", sts.Name, ordinal))
+		}
+		podNames[sts.RackName] = names
+	}
+	return podNames
+}
+
+// GroupPodsByRack returns the given pods by the name of their rack, taken from the RackLabel
+// the operator sets on them, ordered by StatefulSet ordinal. Every rack of the datacenter
+// has an entry, and pods of racks the datacenter does not have are left out.
+func (dc *CassandraDatacenter) GroupPodsByRack(pods []*corev1.Pod) map[string][]*corev1.Pod {
+	podsByRack := map[string][]*corev1.Pod{}
+	for _, rack := range dc.GetRacks() {
+		podsByRack[rack.Name] = []*corev1.Pod{}
+	}
+
+	for _, pod := range pods {
+		rackName := pod.Labels[RackLabel]
+		if rackPods, ok := podsByRack[rackName]; ok {
+			podsByRack[rackName] = append(rackPods, pod)
+		}
+	}
+
+	for _, rackPods := range podsByRack {
+		sort.SliceStable(rackPods, func(i, j int) bool {
+			return getPodOrdinal(rackPods[i].Name) < getPodOrdinal(rackPods[j].Name)
+		})
+	}
+	return podsByRack
+}
+
+// getPodOrdinal returns the StatefulSet ordinal a pod name ends with, or -1 when it does
+// not end with one
+func getPodOrdinal(podName string) int {
+	ordinal, err := strconv.Atoi(podName[strings.LastIndex(podName, "-")+1:])
+	if err != nil {
+		return -1
+	}
+	return ordinal
+}
+
+func (dc *CassandraDatacenter) ShouldGenerateSuperuserSecret() bool {
+	return len(dc.Spec.SuperuserSecretName) == 0
+}
+
+// ValidateSuperuserConfig checks that the superuser settings are unambiguous. The superuser
+// credentials come from the SuperuserSecretName secret when it is set, and otherwise from a
+// secret the operator generates. Users adds further users, so it may not list the superuser
+// secret, and may list a secret only once, as it would otherwise be unclear whether the user
+// is a superuser.
+func (dc *CassandraDatacenter) ValidateSuperuserConfig() error {
+	if name := dc.Spec.SuperuserSecretName; name != "" {
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return fmt.Errorf("superuserSecretName '%s' is not a valid secret name", name)
+		}
+	}
+
+	superuserSecret := dc.GetSuperuserSecretNamespacedName().Name
+	superuserFlags := map[string]bool{}
+	for _, user := range dc.Spec.Users {
+		if user.SecretName == superuserSecret {
+			if dc.ShouldGenerateSuperuserSecret() {
+				return fmt.Errorf("users lists the generated superuser secret %s, which the operator already upserts", superuserSecret)
+			}
+			return fmt.Errorf("users lists the superuserSecretName secret %s, which the operator already upserts", superuserSecret)
+		}
+		if superuser, ok := superuserFlags[user.SecretName]; ok && superuser != user.Superuser {
+			return fmt.Errorf("users lists the secret %s both as a superuser and as a regular user", user.SecretName)
+		}
+		superuserFlags[user.SecretName] = user.Superuser
+	}
+
+	return nil
+}
+
+// EnforcesSuperuserConfig returns whether a datacenter failing ValidateSuperuserConfig is
+// rejected, by the webhook and the controller alike. That is the case until the superuser
+// was first upserted. Existing datacenters only get a warning, so that they keep being
+// reconciled.
+func (dc *CassandraDatacenter) EnforcesSuperuserConfig() bool {
+	return !isStatusTimeSet(dc.Status.SuperUserUpserted)
+}
+
+// SuperuserSecretNeedsUpsert returns whether the superuser has to be upserted from the
+// secret, which is the case when it was never upserted or when the secret was last modified
+// at or after Status.SuperUserUpserted, e.g. because the credentials were rotated. As
+// timestamps only have a precision of seconds, a modification in the same second as the
+// upsert counts as newer.
+func (dc *CassandraDatacenter) SuperuserSecretNeedsUpsert(secret *corev1.Secret) bool {
+	upserted := dc.Status.SuperUserUpserted
+	if !isStatusTimeSet(upserted) {
+		return true
+	}
+
+	lastModified := secret.GetCreationTimestamp()
+	for _, entry := range secret.GetManagedFields() {
+		if entry.Time != nil && lastModified.Before(entry.Time) {
+			lastModified = *entry.Time
+		}
+	}
+
+	return !lastModified.Before(&upserted)
+}
+
+// isStatusTimeSet returns whether a time of the status was recorded, as the operator
+// replaces zero times with the Unix(1, 0) placeholder
+func isStatusTimeSet(t metav1.Time) bool {
+	return !t.IsZero() && !t.Equal(&metav1.Time{Time: time.Unix(1, 0)})
+}
+
+// HasBootstrapped returns whether the datacenter got past its initial bring-up, which is
+// the case once a server node was started and the superuser was upserted. It stays true
+// afterwards, also while the datacenter is stopped.
+func (dc *CassandraDatacenter) HasBootstrapped() bool {
+	return isStatusTimeSet(dc.Status.LastServerNodeStarted) && isStatusTimeSet(dc.Status.SuperUserUpserted)
+}
+
+// GetSuperuserSecretLabels returns the labels of the superuser secret generated by the
+// operator, linking it to the cluster and datacenter that created it
+func (dc *CassandraDatacenter) GetSuperuserSecretLabels() map[string]string {
+	labels := dc.GetDatacenterLabels()
+	oplabels.AddManagedByLabel(labels)
+	return labels
+}
+
+// NewSiblingDatacenter returns a template for a new datacenter named name that joins the
+// cluster of dc. It shares the cluster name, superuser secret, server type, version and
+// image and management API auth with dc, while the size, racks, storage and config are left
+// for the caller to fill in. The superuser secret name is always set, so that a generated
+// superuser secret is reused rather than a new one being generated for the new datacenter.
+// It has to be copied when the new datacenter is in another namespace.
+func (dc *CassandraDatacenter) NewSiblingDatacenter(name string) *CassandraDatacenter {
+	return &CassandraDatacenter{
+		TypeMeta: dc.TypeMeta,
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: dc.Namespace,
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName:         dc.Spec.ClusterName,
+			SuperuserSecretName: dc.GetSuperuserSecretNamespacedName().Name,
+			ServerType:          dc.Spec.ServerType,
+			ServerVersion:       dc.Spec.ServerVersion,
+			ServerImage:         dc.Spec.ServerImage,
+			ManagementApiAuth:   *dc.Spec.ManagementApiAuth.DeepCopy(),
+		},
+	}
+}
+
+func (dc *CassandraDatacenter) GetSuperuserSecretNamespacedName() types.NamespacedName {
+	name := dc.Spec.ClusterName + "-superuser"
+	namespace := dc.ObjectMeta.Namespace
+	if len(dc.Spec.SuperuserSecretName) > 0 {
+		name = dc.Spec.SuperuserSecretName
+	}
+
+	return types.NamespacedName{
+		Name:      name,
+		Namespace: namespace,
+	}
+}
+
+// GetConfigAsJSON gets a JSON-encoded string suitable for passing to configBuilder
+func (dc *CassandraDatacenter) GetConfigAsJSON(config []byte) (string, error) {
 
 	// We use the cluster seed-service name here for the seed list as it will
 	// resolve to the seed nodes. This obviates the need to update the
@@ -611,9 +1907,79 @@ func (dc *CassandraDatacenter) GetConfigAsJSON(config []byte) (string, error) {
 		internode,
 		internodeSSL)
 
+	// The server defaults already match the default data directory, so the paths are
+	// only rendered when a different one is used.
+	if dataDir := dc.GetDataDirectory(); dataDir != DefaultDataDirectory {
+		cassandraYaml := modelValues["cassandra-yaml"].(serverconfig.NodeConfig)
+		cassandraYaml["data_file_directories"] = []string{path.Join(dataDir, "data")}
+		cassandraYaml["commitlog_directory"] = path.Join(dataDir, "commitlog")
+		cassandraYaml["hints_directory"] = path.Join(dataDir, "hints")
+		cassandraYaml["saved_caches_directory"] = path.Join(dataDir, "saved_caches")
+	}
+
+	if dc.Spec.LogLevel != "" {
+		modelValues["logback-xml"] = serverconfig.NodeConfig{
+			"root-log-level": dc.Spec.LogLevel,
+		}
+	}
+
+	durabilityValues, err := dc.GetDurabilityConfigValues()
+	if err != nil {
+		return "", err
+	}
+	timeoutValues, err := dc.GetRequestTimeoutConfigValues()
+	if err != nil {
+		return "", err
+	}
+	memoryValues, err := dc.GetMemtablesAndCachesConfigValues()
+	if err != nil {
+		return "", err
+	}
+	listenValues, err := dc.GetListenInterfaceConfigValues()
+	if err != nil {
+		return "", err
+	}
+	structuredYamlKeys := []string{}
+	cassandraYaml := modelValues["cassandra-yaml"].(serverconfig.NodeConfig)
+	for _, values := range []map[string]interface{}{durabilityValues, timeoutValues, memoryValues, listenValues} {
+		for key, value := range values {
+			cassandraYaml[key] = value
+			structuredYamlKeys = append(structuredYamlKeys, key)
+		}
+	}
+
+	rackdcProperties := serverconfig.NodeConfig{}
+	if dc.Spec.PreferLocal {
+		rackdcProperties["prefer_local"] = true
+	}
+	if dc.Spec.DcSuffix != "" {
+		rackdcProperties["dc_suffix"] = dc.Spec.DcSuffix
+	}
+	if len(rackdcProperties) > 0 {
+		modelValues["cassandra-rackdc-properties"] = rackdcProperties
+	}
+
+	promConf := serverconfig.NodeConfig{}
+	if dc.Spec.EnablePrometheus {
+		promConf["enabled"] = true
+	}
+	if dc.Spec.PrometheusPort != 0 {
+		promConf["port"] = dc.Spec.PrometheusPort
+	}
+	if len(promConf) > 0 {
+		modelValues["10-write-prom-conf"] = promConf
+	}
+
+	// Config adding further additional-jvm-opts is merged into the same list
+	for section, options := range dc.GetGCLoggingJvmOptions() {
+		modelValues[section] = serverconfig.NodeConfig{
+			"additional-jvm-opts": options,
+		}
+	}
+
 	var modelBytes []byte
 
-	modelBytes, err := json.Marshal(modelValues)
+	modelBytes, err = json.Marshal(modelValues)
 	if err != nil {
 		return "", err
 	}
@@ -631,6 +1997,43 @@ func (dc *CassandraDatacenter) GetConfigAsJSON(config []byte) (string, error) {
 			return "", errors.Wrap(err, "Error parsing Spec.Config for CassandraDatacenter resource")
 		}
 
+		if reserved := getReservedConfigKeys(configParsed); len(reserved) > 0 {
+			if !dc.AllowsReservedConfigKeys() {
+				return "", errors.Errorf("Spec.Config for CassandraDatacenter resource sets reserved keys: %s", strings.Join(reserved, ", "))
+			}
+
+			// Merging would combine both values into an array, so drop the model values
+			// the user config replaces
+			for _, key := range reserved {
+				_ = modelParsed.DeleteP(key)
+			}
+		}
+
+		// The user config overrides LogLevel, PreferLocal, DcSuffix, Durability,
+		// RequestTimeouts, MemtablesAndCaches, the listen interface, EnablePrometheus and
+		// PrometheusPort
+		structuredKeys := []string{
+			"logback-xml.root-log-level",
+			"cassandra-rackdc-properties.prefer_local",
+			"cassandra-rackdc-properties.dc_suffix",
+			"10-write-prom-conf.enabled",
+			"10-write-prom-conf.port",
+		}
+		for _, key := range structuredYamlKeys {
+			structuredKeys = append(structuredKeys, "cassandra-yaml."+key)
+		}
+		for _, key := range structuredKeys {
+			if configParsed.ExistsP(key) {
+				_ = modelParsed.DeleteP(key)
+			}
+		}
+
+		if stripped := stripLegacyRpcConfigKeys(dc, configParsed); len(stripped) > 0 {
+			log.Info("Stripped rpc settings from config which are unsupported by the server version",
+				"datacenter", dc.Name, "serverType", dc.Spec.ServerType,
+				"serverVersion", dc.Spec.ServerVersion, "keys", stripped)
+		}
+
 		if err := modelParsed.Merge(configParsed); err != nil {
 			return "", errors.Wrap(err, "Error merging Spec.Config for CassandraDatacenter resource")
 		}
@@ -639,6 +2042,457 @@ func (dc *CassandraDatacenter) GetConfigAsJSON(config []byte) (string, error) {
 	return modelParsed.String(), nil
 }
 
+// ServiceMonitorSpec holds the values needed to render a Prometheus Operator
+// ServiceMonitor that scrapes the datacenter's prometheus endpoint
+type ServiceMonitorSpec struct {
+	// Labels selecting the service exposing the prometheus port
+	Selector map[string]string
+	// Name of the service port to scrape
+	Port string
+	// HTTP path of the metrics endpoint
+	Path string
+}
+
+// getPrometheusConf returns the 10-write-prom-conf section of the rendered config, which
+// EnablePrometheus, PrometheusPort and Config fill in, or nil if it has none
+func (dc *CassandraDatacenter) getPrometheusConf() map[string]interface{} {
+	rendered, err := dc.GetConfigAsJSON(dc.Spec.Config)
+	if err != nil {
+		return nil
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(rendered), &config); err != nil {
+		return nil
+	}
+
+	promConf, _ := config["10-write-prom-conf"].(map[string]interface{})
+	return promConf
+}
+
+// GetServiceMonitorSpec returns the ServiceMonitor values for the datacenter, or nil if
+// the prometheus collectd writer is not enabled through EnablePrometheus or
+// 10-write-prom-conf in the config.
+func (dc *CassandraDatacenter) GetServiceMonitorSpec() *ServiceMonitorSpec {
+	promConf := dc.getPrometheusConf()
+	if enabled, ok := promConf["enabled"].(bool); !ok || !enabled {
+		return nil
+	}
+
+	selector := dc.GetDatacenterLabels()
+	selector[PromMetricsLabel] = "true"
+
+	return &ServiceMonitorSpec{
+		Selector: selector,
+		Port:     "prometheus",
+		Path:     "/metrics",
+	}
+}
+
+// GetClientNativePort returns the native port CQL clients should connect to, and whether
+// they have to use TLS, from the client_encryption_options of the rendered cassandra.yaml.
+// With client encryption enabled, clients use native_transport_port_ssl when it is set,
+// and otherwise native_transport_port, where TLS is required unless it is optional.
+func (dc *CassandraDatacenter) GetClientNativePort() (int, bool, error) {
+	config, err := dc.GetConfigAsJSON(dc.Spec.Config)
+	if err != nil {
+		return 0, false, err
+	}
+
+	var parsed struct {
+		CassandraYaml struct {
+			NativePort       *int `json:"native_transport_port"`
+			NativeSSLPort    *int `json:"native_transport_port_ssl"`
+			ClientEncryption struct {
+				Enabled  bool `json:"enabled"`
+				Optional bool `json:"optional"`
+			} `json:"client_encryption_options"`
+		} `json:"cassandra-yaml"`
+	}
+	if err := json.Unmarshal([]byte(config), &parsed); err != nil {
+		return 0, false, err
+	}
+
+	cassandraYaml := parsed.CassandraYaml
+	nativePort := DefaultNativePort
+	if cassandraYaml.NativePort != nil {
+		nativePort = *cassandraYaml.NativePort
+	}
+
+	if !cassandraYaml.ClientEncryption.Enabled {
+		return nativePort, false, nil
+	}
+	if cassandraYaml.NativeSSLPort != nil {
+		return *cassandraYaml.NativeSSLPort, true, nil
+	}
+	return nativePort, !cassandraYaml.ClientEncryption.Optional, nil
+}
+
+// CQLConnectionInfo holds what a CQL client needs to connect to the datacenter
+type CQLConnectionInfo struct {
+	// Host is the DNS name of the datacenter service
+	Host string
+	// Port is the native port clients connect to
+	Port int
+	// TLS is whether clients have to use TLS
+	TLS bool
+	// SuperuserSecret is the secret holding the superuser credentials
+	SuperuserSecret types.NamespacedName
+	// UsernameKey and PasswordKey are the keys of the credentials in SuperuserSecret
+	UsernameKey string
+	PasswordKey string
+}
+
+// GetCQLConnectionInfo returns the connection details CQL clients should use, the same
+// ones the operator uses for the datacenter service and the superuser
+func (dc *CassandraDatacenter) GetCQLConnectionInfo() (CQLConnectionInfo, error) {
+	port, tls, err := dc.GetClientNativePort()
+	if err != nil {
+		return CQLConnectionInfo{}, err
+	}
+
+	return CQLConnectionInfo{
+		Host:            fmt.Sprintf("%s.%s.svc", dc.GetDatacenterServiceName(), dc.Namespace),
+		Port:            port,
+		TLS:             tls,
+		SuperuserSecret: dc.GetSuperuserSecretNamespacedName(),
+		UsernameKey:     SuperuserSecretUsernameKey,
+		PasswordKey:     SuperuserSecretPasswordKey,
+	}, nil
+}
+
+// GetPrometheusPort returns the port of the prometheus collectd writer, which can be set
+// through PrometheusPort or 10-write-prom-conf in the config
+func (dc *CassandraDatacenter) GetPrometheusPort() int {
+	promConf := dc.getPrometheusConf()
+	if port, ok := promConf["port"].(float64); ok && port > 0 && port == float64(int(port)) {
+		return int(port)
+	}
+	return DefaultPrometheusPort
+}
+
+// GetStoragePorts returns the storage_port and ssl_storage_port of the rendered
+// cassandra.yaml, which nodes use to gossip and stream with each other, falling back to
+// DefaultInternodePort and DefaultInternodeSSLPort when they are not set or the config
+// cannot be rendered
+func (dc *CassandraDatacenter) GetStoragePorts() (int, int) {
+	storagePort, sslStoragePort := DefaultInternodePort, DefaultInternodeSSLPort
+
+	config, err := dc.GetConfigAsJSON(dc.Spec.Config)
+	if err != nil {
+		return storagePort, sslStoragePort
+	}
+
+	var parsed struct {
+		CassandraYaml struct {
+			StoragePort    *int `json:"storage_port"`
+			SSLStoragePort *int `json:"ssl_storage_port"`
+		} `json:"cassandra-yaml"`
+	}
+	if err := json.Unmarshal([]byte(config), &parsed); err != nil {
+		return storagePort, sslStoragePort
+	}
+
+	if port := parsed.CassandraYaml.StoragePort; port != nil && *port > 0 {
+		storagePort = *port
+	}
+	if port := parsed.CassandraYaml.SSLStoragePort; port != nil && *port > 0 {
+		sslStoragePort = *port
+	}
+	return storagePort, sslStoragePort
+}
+
+// GetReconcileInterval returns the duration after which a fully reconciled datacenter is
+// reconciled again, zero meaning it is not requeued
+func (dc *CassandraDatacenter) GetReconcileInterval() time.Duration {
+	return time.Duration(dc.Spec.ReconcileIntervalSeconds) * time.Second
+}
+
+// IsConfigValidationEnabled returns whether the optional config validations apply
+func (dc *CassandraDatacenter) IsConfigValidationEnabled() bool {
+	return !dc.Spec.DisableConfigValidation
+}
+
+// AllowsReservedConfigKeys returns whether the user config may set ReservedConfigKeys
+func (dc *CassandraDatacenter) AllowsReservedConfigKeys() bool {
+	return dc.Spec.ForceConfigOverride || !dc.IsConfigValidationEnabled()
+}
+
+// GetSystemAuthReplicationSettings returns the system_auth replication in the format of the
+// management API keyspace endpoints, ordered by datacenter name
+func (dc *CassandraDatacenter) GetSystemAuthReplicationSettings() []map[string]string {
+	dcNames := []string{}
+	for dcName := range dc.Spec.SystemAuthReplication {
+		dcNames = append(dcNames, dcName)
+	}
+	sort.Strings(dcNames)
+
+	settings := []map[string]string{}
+	for _, dcName := range dcNames {
+		settings = append(settings, map[string]string{
+			"dc_name":            dcName,
+			"replication_factor": fmt.Sprintf("%d", dc.Spec.SystemAuthReplication[dcName]),
+		})
+	}
+	return settings
+}
+
+// GetSystemAuthAlterKeyspaceCQL returns the CQL statement applying SystemAuthReplication, or
+// an empty string if it is not set
+func (dc *CassandraDatacenter) GetSystemAuthAlterKeyspaceCQL() string {
+	if len(dc.Spec.SystemAuthReplication) == 0 {
+		return ""
+	}
+
+	replication := []string{"'class': 'NetworkTopologyStrategy'"}
+	for _, setting := range dc.GetSystemAuthReplicationSettings() {
+		replication = append(replication, fmt.Sprintf("'%s': %s", setting["dc_name"], setting["replication_factor"]))
+	}
+	return fmt.Sprintf("ALTER KEYSPACE system_auth WITH replication = {%s}", strings.Join(replication, ", "))
+}
+
+// GetReservedConfigKeys returns the keys of ReservedConfigKeys which are set in the
+// given config
+func GetReservedConfigKeys(config []byte) ([]string, error) {
+	configParsed, err := gabs.ParseJSON(config)
+	if err != nil {
+		return nil, err
+	}
+	return getReservedConfigKeys(configParsed), nil
+}
+
+func getReservedConfigKeys(config *gabs.Container) []string {
+	reserved := []string{}
+	for _, key := range ReservedConfigKeys {
+		if config.ExistsP(key) {
+			reserved = append(reserved, key)
+		}
+	}
+	return reserved
+}
+
+// SupportsLegacyRpc returns whether the server version supports the thrift rpc settings
+// listed in LegacyRpcConfigKeys
+func (dc *CassandraDatacenter) SupportsLegacyRpc() bool {
+	return dc.Spec.ServerType == "cassandra" && strings.HasPrefix(dc.Spec.ServerVersion, "3.")
+}
+
+// stripLegacyRpcConfigKeys deletes the LegacyRpcConfigKeys from the config if the server
+// version does not support them, so the same config can be kept when upgrading from
+// Cassandra 3.11. It returns the deleted keys.
+func stripLegacyRpcConfigKeys(dc *CassandraDatacenter, config *gabs.Container) []string {
+	stripped := []string{}
+	if dc.SupportsLegacyRpc() {
+		return stripped
+	}
+	for _, key := range LegacyRpcConfigKeys {
+		if config.ExistsP(key) {
+			_ = config.DeleteP(key)
+			stripped = append(stripped, key)
+		}
+	}
+	return stripped
+}
+
+// GetConfigSections returns the top level sections the config builder accepts in the
+// rendered config for the server type and version: the config files it renders and the
+// sections the operator fills in itself
+func (dc *CassandraDatacenter) GetConfigSections() []string {
+	sections := []string{
+		"cluster-info",
+		"datacenter-info",
+		"cassandra-yaml",
+		"cassandra-env-sh",
+		"cassandra-rackdc-properties",
+		"logback-xml",
+		"10-statsd-conf",
+		"10-write-graphite-conf",
+		"10-write-prom-conf",
+	}
+	if dc.Spec.ServerType == "cassandra" && strings.HasPrefix(dc.Spec.ServerVersion, "3.") {
+		return append(sections, "jvm-options")
+	}
+	sections = append(sections, "jvm-server-options", "jvm8-server-options", "jvm11-server-options")
+	if dc.Spec.ServerType == "dse" {
+		sections = append(sections, "dse-yaml", "dse-default")
+	}
+	return sections
+}
+
+// ValidateConfigStructure checks the config rendered from Config for structural mistakes
+// the config builder would reject, without running it: sections which are not config
+// files of the server version, keys in dot notation instead of nested objects and sections
+// which are not objects. It returns one issue per mistake.
+func (dc *CassandraDatacenter) ValidateConfigStructure() ([]string, error) {
+	rendered, err := dc.GetConfigAsJSON(dc.Spec.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	var config map[string]interface{}
+	if err := json.Unmarshal([]byte(rendered), &config); err != nil {
+		return nil, err
+	}
+
+	sections := map[string]bool{}
+	for _, section := range dc.GetConfigSections() {
+		sections[section] = true
+	}
+
+	issues := []string{}
+	for key, value := range config {
+		if !sections[key] {
+			if prefix := strings.SplitN(key, ".", 2)[0]; prefix != key && sections[prefix] {
+				issues = append(issues, fmt.Sprintf("config key %s uses dot notation, it has to be nested in %s", key, prefix))
+			} else {
+				issues = append(issues, fmt.Sprintf("config section %s is not a config file of %s %s",
+					key, dc.Spec.ServerType, dc.Spec.ServerVersion))
+			}
+			continue
+		}
+		if _, ok := value.(map[string]interface{}); !ok {
+			issues = append(issues, fmt.Sprintf("config section %s is not an object", key))
+		}
+	}
+	sort.Strings(issues)
+	return issues, nil
+}
+
+// getStructuredConfigPaths returns, by spec field, the config paths in dot notation which
+// the structured config fields set in the spec render, failing like GetConfigAsJSON when
+// a field cannot be rendered
+func (dc *CassandraDatacenter) getStructuredConfigPaths() (map[string][]string, error) {
+	paths := map[string][]string{}
+
+	yamlFields := []struct {
+		field  string
+		values func() (map[string]interface{}, error)
+	}{
+		{"durability", dc.GetDurabilityConfigValues},
+		{"requestTimeouts", dc.GetRequestTimeoutConfigValues},
+		{"memtablesAndCaches", dc.GetMemtablesAndCachesConfigValues},
+		{"networking", dc.GetListenInterfaceConfigValues},
+	}
+	for _, yamlField := range yamlFields {
+		values, err := yamlField.values()
+		if err != nil {
+			return nil, err
+		}
+		for key := range values {
+			paths[yamlField.field] = append(paths[yamlField.field], "cassandra-yaml."+key)
+		}
+		sort.Strings(paths[yamlField.field])
+	}
+
+	if dc.Spec.LogLevel != "" {
+		paths["logLevel"] = []string{"logback-xml.root-log-level"}
+	}
+	if dc.Spec.PreferLocal {
+		paths["preferLocal"] = []string{"cassandra-rackdc-properties.prefer_local"}
+	}
+	if dc.Spec.DcSuffix != "" {
+		paths["dcSuffix"] = []string{"cassandra-rackdc-properties.dc_suffix"}
+	}
+	if dc.Spec.EnablePrometheus {
+		paths["enablePrometheus"] = []string{"10-write-prom-conf.enabled"}
+	}
+	if dc.Spec.PrometheusPort != 0 {
+		paths["prometheusPort"] = []string{"10-write-prom-conf.port"}
+	}
+	for section := range dc.GetGCLoggingJvmOptions() {
+		paths["enableGCLogging"] = append(paths["enableGCLogging"], section+".additional-jvm-opts")
+	}
+	sort.Strings(paths["enableGCLogging"])
+
+	return paths, nil
+}
+
+// isConfigPathSupported returns whether the server type and version support the config
+// path in dot notation: its section has to be one of GetConfigSections, the legacy rpc
+// settings are only supported by Cassandra 3.x and the versionedConfigKeys only by the
+// versions listed there
+func (dc *CassandraDatacenter) isConfigPathSupported(configPath string) bool {
+	section := strings.SplitN(configPath, ".", 2)[0]
+	supported := false
+	for _, known := range dc.GetConfigSections() {
+		if section == known {
+			supported = true
+			break
+		}
+	}
+	if !supported {
+		return false
+	}
+
+	if !dc.SupportsLegacyRpc() {
+		for _, key := range LegacyRpcConfigKeys {
+			if configPath == key {
+				return false
+			}
+		}
+	}
+
+	if versions, ok := versionedConfigKeys[configPath]; ok {
+		for _, prefix := range versions[dc.Spec.ServerType] {
+			if strings.HasPrefix(dc.Spec.ServerVersion, prefix) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// GetUnsupportedStructuredConfigFields returns, by spec field, the config paths of the
+// structured config fields set in the spec which the server type and version do not
+// support, and would otherwise be ignored or fail the config builder
+func (dc *CassandraDatacenter) GetUnsupportedStructuredConfigFields() (map[string][]string, error) {
+	paths, err := dc.getStructuredConfigPaths()
+	if err != nil {
+		return nil, err
+	}
+
+	unsupported := map[string][]string{}
+	for field, fieldPaths := range paths {
+		for _, configPath := range fieldPaths {
+			if !dc.isConfigPathSupported(configPath) {
+				unsupported[field] = append(unsupported[field], configPath)
+			}
+		}
+	}
+	return unsupported, nil
+}
+
+// GetConfigHash returns a hash of the rendered server config. With ConfigSecret this is the
+// hash the operator keeps in the ConfigHashAnnotation of the datacenter, otherwise it is the
+// hash of the config rendered from Config, which is stable as long as Config is unchanged.
+func (dc *CassandraDatacenter) GetConfigHash() (string, error) {
+	if len(dc.Spec.ConfigSecret) > 0 {
+		if configHash, ok := dc.Annotations[ConfigHashAnnotation]; ok {
+			return configHash, nil
+		}
+		return "", fmt.Errorf("datacenter %s is missing %s annotation", dc.Name, ConfigHashAnnotation)
+	}
+
+	config, err := dc.GetConfigAsJSON(dc.Spec.Config)
+	if err != nil {
+		return "", err
+	}
+
+	hashBytes := sha256.Sum256([]byte(config))
+	return base64.StdEncoding.EncodeToString(hashBytes[:]), nil
+}
+
+// GetConfigHashAnnotation returns the key and value of the config hash annotation for the
+// server pods, so that config changes roll the pods and config drift can be detected
+func (dc *CassandraDatacenter) GetConfigHashAnnotation() (string, string, error) {
+	configHash, err := dc.GetConfigHash()
+	if err != nil {
+		return "", "", err
+	}
+	return ConfigHashAnnotation, configHash, nil
+}
+
 // Gets the defined CQL port for NodePort.
 // 0 will be returned if NodePort is not configured.
 // The SSL port will be returned if it is defined,
@@ -683,7 +2537,7 @@ func namedPort(name string, port int) corev1.ContainerPort {
 func (dc *CassandraDatacenter) GetContainerPorts() ([]corev1.ContainerPort, error) {
 
 	nativePort := DefaultNativePort
-	internodePort := DefaultInternodePort
+	internodePort, internodeSSLPort := dc.GetStoragePorts()
 
 	// Note: Port Names cannot be more than 15 characters
 
@@ -691,10 +2545,10 @@ func (dc *CassandraDatacenter) GetContainerPorts() ([]corev1.ContainerPort, erro
 		namedPort("native", nativePort),
 		namedPort("tls-native", 9142),
 		namedPort("internode", internodePort),
-		namedPort("tls-internode", 7001),
+		namedPort("tls-internode", internodeSSLPort),
 		namedPort("jmx", 7199),
-		namedPort("mgmt-api-http", 8080),
-		namedPort("prometheus", 9103),
+		namedPort(ManagementApiPortName, 8080),
+		namedPort("prometheus", dc.GetPrometheusPort()),
 		namedPort("thrift", 9160),
 	}
 
@@ -748,9 +2602,223 @@ func (dc *CassandraDatacenter) GetContainerPorts() ([]corev1.ContainerPort, erro
 		}
 	}
 
+	if err := checkPortConflicts(ports); err != nil {
+		return nil, err
+	}
+
 	return ports, nil
 }
 
+// checkPortConflicts returns an error naming the first ports which use the same number
+func checkPortConflicts(ports []corev1.ContainerPort) error {
+	names := map[int32]string{}
+	for _, port := range ports {
+		if name, ok := names[port.ContainerPort]; ok {
+			return fmt.Errorf("container ports %s and %s both use port %d", name, port.Name, port.ContainerPort)
+		}
+		names[port.ContainerPort] = port.Name
+	}
+	return nil
+}
+
+// RackUpdateStrategy describes how the StatefulSet of a rack should be updated
+type RackUpdateStrategy struct {
+	RackName string
+
+	// Paused racks do not receive any configuration or image changes
+	Paused bool
+
+	// The rolling update partition for the StatefulSet of the rack. Only pods with an
+	// ordinal greater than or equal to the partition are updated. Nil when no partition
+	// needs to be set.
+	Partition *int32
+}
+
+// GetRackUpdateStrategy returns the update strategy for the rack at rackIndex in GetRacks(),
+// given the number of nodes in that rack. When CanaryUpgrade is turned on only the first
+// rack is updated, limited to CanaryUpgradeCount nodes, while the other racks are paused by
+// setting their partition to their node count.
+func (dc *CassandraDatacenter) GetRackUpdateStrategy(rackIndex int, nodeCount int) RackUpdateStrategy {
+	strategy := RackUpdateStrategy{
+		RackName: dc.GetRacks()[rackIndex].Name,
+	}
+
+	if !dc.Spec.CanaryUpgrade {
+		return strategy
+	}
+
+	partition := dc.GetRackCanaryPartition(rackIndex, nodeCount)
+	strategy.Paused = rackIndex > 0
+	strategy.Partition = &partition
+
+	return strategy
+}
+
+// GetRackCanaryPartition returns the rolling update partition of the StatefulSet of the rack
+// at rackIndex in GetRacks(), given the number of nodes in that rack. While CanaryUpgrade is
+// turned on, this is the node count for the paused racks, so that none of their pods are
+// updated, and for the first rack the number of its nodes beyond CanaryUpgradeCount, or 0
+// when the count is 0 or exceeds the rack. Otherwise it is 0, so that recomputing the
+// partitions once CanaryUpgrade is turned off resumes the update of every rack.
+func (dc *CassandraDatacenter) GetRackCanaryPartition(rackIndex int, nodeCount int) int32 {
+	if !dc.Spec.CanaryUpgrade {
+		return 0
+	}
+	if rackIndex > 0 {
+		return int32(nodeCount)
+	}
+
+	canaryCount := dc.Spec.CanaryUpgradeCount
+	if canaryCount == 0 || canaryCount > int32(nodeCount) {
+		return 0
+	}
+	return int32(nodeCount) - canaryCount
+}
+
+// GetRackUpdateStrategies returns the update strategy for every rack in GetRacks(), with
+// nodes distributed across racks the same way the operator does
+func (dc *CassandraDatacenter) GetRackUpdateStrategies() []RackUpdateStrategy {
+	racks := dc.GetRacks()
+	rackNodeCounts := dc.GetRackNodeCounts(int(dc.Spec.Size))
+
+	strategies := make([]RackUpdateStrategy, 0, len(racks))
+	for idx := range racks {
+		strategies = append(strategies, dc.GetRackUpdateStrategy(idx, rackNodeCounts[idx]))
+	}
+
+	return strategies
+}
+
+// SpecChangeImpact describes how the operator acts on a changed spec field
+type SpecChangeImpact string
+
+const (
+	// RequiresRollingUpdate fields change the statefulsets' pod template, so the pods are
+	// restarted one at a time
+	RequiresRollingUpdate SpecChangeImpact = "RequiresRollingUpdate"
+
+	// StatusOnly fields are acted on without restarting the existing pods, e.g. by scaling,
+	// updating services or upserting users
+	StatusOnly SpecChangeImpact = "StatusOnly"
+
+	// Ignored fields have no lasting effect, as the operator sets them back or no longer
+	// uses them
+	Ignored SpecChangeImpact = "Ignored"
+)
+
+// SpecFieldChange is a changed CassandraDatacenterSpec field and its impact
+type SpecFieldChange struct {
+	// The Go name of the spec field
+	Field  string
+	Impact SpecChangeImpact
+}
+
+// specFieldImpacts lists the spec fields which do not require a rolling update. Any other
+// field is assumed to end up in the pod template.
+var specFieldImpacts = map[string]SpecChangeImpact{
+	"Size":                                   StatusOnly,
+	"ReplaceNodes":                           StatusOnly,
+	"NodeReplacementStrategy":                Ignored,
+	"Stopped":                                StatusOnly,
+	"CanaryUpgrade":                          StatusOnly,
+	"CanaryUpgradeCount":                     StatusOnly,
+	"SuperuserSecretName":                    StatusOnly,
+	"Users":                                  StatusOnly,
+	"AdditionalServiceConfig":                StatusOnly,
+	"AllPodsServicePublishNotReadyAddresses": StatusOnly,
+	"ManagementApiService":                   StatusOnly,
+	"AlwaysPublishNotReadySeeds":             StatusOnly,
+	"SystemAuthReplication":                  StatusOnly,
+	"SeedsPerRack":                           StatusOnly,
+	"RackDistributionStrategy":               StatusOnly,
+	"MinNodesPerRack":                        Ignored,
+	"RequireDistinctRackZones":               Ignored,
+	"ReconcileIntervalSeconds":               Ignored,
+	"AutoCleanupAfterScaleUp":                Ignored,
+	"RollingRestartRequested":                Ignored,
+	"ForceUpgradeRacks":                      Ignored,
+	"Reaper":                                 Ignored,
+	"ForceConfigOverride":                    Ignored,
+	"DisableConfigValidation":                Ignored,
+	"StrictConfigValidation":                 Ignored,
+	"RequireImageTags":                       Ignored,
+	"DebugConfigBuilder":                     Ignored,
+}
+
+// DiffSpecs returns the fields which differ between the specs of oldDc and newDc, in the
+// order they are declared, classified by how the operator acts on them
+func DiffSpecs(oldDc *CassandraDatacenter, newDc *CassandraDatacenter) []SpecFieldChange {
+	oldSpec := reflect.ValueOf(oldDc.Spec)
+	newSpec := reflect.ValueOf(newDc.Spec)
+	specType := oldSpec.Type()
+
+	changes := []SpecFieldChange{}
+	for i := 0; i < specType.NumField(); i++ {
+		if reflect.DeepEqual(oldSpec.Field(i).Interface(), newSpec.Field(i).Interface()) {
+			continue
+		}
+
+		name := specType.Field(i).Name
+		impact, ok := specFieldImpacts[name]
+		if !ok {
+			impact = RequiresRollingUpdate
+		}
+		if name == "Racks" && racksOnlyAppended(oldDc.Spec.Racks, newDc.Spec.Racks) {
+			// New racks get new statefulsets, the existing ones are left alone
+			impact = StatusOnly
+		}
+		changes = append(changes, SpecFieldChange{Field: name, Impact: impact})
+	}
+	return changes
+}
+
+func racksOnlyAppended(oldRacks []Rack, newRacks []Rack) bool {
+	return len(oldRacks) > 0 && len(newRacks) > len(oldRacks) &&
+		reflect.DeepEqual(oldRacks, newRacks[:len(oldRacks)])
+}
+
+// GetRackNodeCounts returns the number of nodes of each rack in GetRacks() for the given
+// number of nodes, distributed according to RackDistributionStrategy
+func (dc *CassandraDatacenter) GetRackNodeCounts(nodeCount int) []int {
+	return SplitRacksWithStrategy(nodeCount, dc.GetRacks(), dc.Spec.RackDistributionStrategy)
+}
+
+// SplitRacksWithStrategy distributes the nodes evenly over the racks, giving the extra
+// nodes to the racks the strategy picks. An empty or unknown strategy behaves like
+// FirstRacks, which is what SplitRacks does.
+func SplitRacksWithStrategy(nodeCount int, racks []Rack, strategy string) []int {
+	rackCount := len(racks)
+	var order []int
+	switch strategy {
+	case RackDistributionLastRacks:
+		for idx := rackCount - 1; idx >= 0; idx-- {
+			order = append(order, idx)
+		}
+	case RackDistributionPreferredRacks:
+		var others []int
+		for idx, rack := range racks {
+			if rack.PreferExtraNodes {
+				order = append(order, idx)
+			} else {
+				others = append(others, idx)
+			}
+		}
+		order = append(order, others...)
+	default:
+		return SplitRacks(nodeCount, rackCount)
+	}
+
+	topology := make([]int, rackCount)
+	for idx := range topology {
+		topology[idx] = nodeCount / rackCount
+	}
+	for _, idx := range order[:nodeCount%rackCount] {
+		topology[idx]++
+	}
+
+	return topology
+}
+
 func SplitRacks(nodeCount, rackCount int) []int {
 	nodesPerRack, extraNodes := nodeCount/rackCount, nodeCount%rackCount
 
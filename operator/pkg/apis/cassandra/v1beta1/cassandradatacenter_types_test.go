@@ -5,10 +5,14 @@ package v1beta1
 
 import (
 	"testing"
+	"time"
 
+	"github.com/k8ssandra/cass-operator/operator/pkg/oplabels"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 )
 
 func TestCassandraDatacenter_GetServerImage(t *testing.T) {
@@ -64,6 +68,9 @@ func TestCassandraDatacenter_GetServerImage(t *testing.T) {
 }
 
 func Test_GenerateBaseConfigString(t *testing.T) {
+	hintedHandoffDisabled := false
+	memtableHeapSpace := int64(2048)
+	rowCacheSize := int64(0)
 	tests := []struct {
 		name      string
 		dc        *CassandraDatacenter
@@ -98,6 +105,348 @@ func Test_GenerateBaseConfigString(t *testing.T) {
 			want:      "",
 			errString: "Error parsing Spec.Config for CassandraDatacenter resource: invalid character ':' after top-level value",
 		},
+		{
+			name: "Custom data directory",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					StorageConfig: StorageConfig{
+						DataDirectory: "/data/cassandra/",
+					},
+				},
+			},
+			want:      `{"cassandra-yaml":{"commitlog_directory":"/data/cassandra/commitlog","data_file_directories":["/data/cassandra/data"],"hints_directory":"/data/cassandra/hints","saved_caches_directory":"/data/cassandra/saved_caches"},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
+		{
+			name: "Reserved keys",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					Config:      []byte(`{"cluster-info":{"seeds":"10.0.0.1"},"cassandra-yaml":{"listen_address":"10.0.0.2"}}`),
+				},
+			},
+			want:      "",
+			errString: "Spec.Config for CassandraDatacenter resource sets reserved keys: cluster-info.seeds, cassandra-yaml.listen_address",
+		},
+		{
+			name: "Parse error with DisableConfigValidation",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName:             "exampleCluster",
+					Config:                  []byte(`{"cassandra-yaml":`),
+					DisableConfigValidation: true,
+				},
+			},
+			want:      "",
+			errString: "Error parsing Spec.Config for CassandraDatacenter resource: unexpected end of JSON input",
+		},
+		{
+			name: "Reserved keys with ForceConfigOverride",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName:         "exampleCluster",
+					Config:              []byte(`{"cluster-info":{"seeds":"10.0.0.1"}}`),
+					ForceConfigOverride: true,
+				},
+			},
+			want:      `{"cassandra-yaml":{},"cluster-info":{"name":"exampleCluster","seeds":"10.0.0.1"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
+		{
+			name: "Log level",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					LogLevel:    "DEBUG",
+				},
+			},
+			want:      `{"cassandra-yaml":{},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0},"logback-xml":{"root-log-level":"DEBUG"}}`,
+			errString: "",
+		},
+		{
+			name: "Log level overridden by config",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					LogLevel:    "DEBUG",
+					Config:      []byte(`{"logback-xml":{"root-log-level":"TRACE"}}`),
+				},
+			},
+			want:      `{"cassandra-yaml":{},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0},"logback-xml":{"root-log-level":"TRACE"}}`,
+			errString: "",
+		},
+		{
+			name: "Rackdc properties",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					PreferLocal: true,
+					DcSuffix:    "_east",
+				},
+			},
+			want:      `{"cassandra-rackdc-properties":{"dc_suffix":"_east","prefer_local":true},"cassandra-yaml":{},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
+		{
+			name: "Rackdc properties overridden by config",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					PreferLocal: true,
+					DcSuffix:    "_east",
+					Config:      []byte(`{"cassandra-rackdc-properties":{"dc_suffix":"_west"}}`),
+				},
+			},
+			want:      `{"cassandra-rackdc-properties":{"dc_suffix":"_west","prefer_local":true},"cassandra-yaml":{},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
+		{
+			name: "Durability settings",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					Durability: &DurabilityConfig{
+						HintedHandoffEnabled:   &hintedHandoffDisabled,
+						MaxHintWindow:          "1h",
+						HintedHandoffThrottle:  "2Mi",
+						BatchlogReplayThrottle: "512Ki",
+					},
+				},
+			},
+			want:      `{"cassandra-yaml":{"batchlog_replay_throttle_in_kb":512,"hinted_handoff_enabled":false,"hinted_handoff_throttle_in_kb":2048,"max_hint_window_in_ms":3600000},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
+		{
+			name: "Commitlog sync settings",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					Durability: &DurabilityConfig{
+						CommitlogSync:       "periodic",
+						CommitlogSyncPeriod: "5s",
+					},
+				},
+			},
+			want:      `{"cassandra-yaml":{"commitlog_sync":"periodic","commitlog_sync_period_in_ms":5000},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
+		{
+			name: "Group commitlog sync settings",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					Durability: &DurabilityConfig{
+						CommitlogSync:            "group",
+						CommitlogSyncGroupWindow: "15ms",
+					},
+				},
+			},
+			want:      `{"cassandra-yaml":{"commitlog_sync":"group","commitlog_sync_group_window_in_ms":15},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
+		{
+			name: "Group commitlog sync without window",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					Durability: &DurabilityConfig{
+						CommitlogSync: "group",
+					},
+				},
+			},
+			want:      ``,
+			errString: "commitlogSyncGroupWindow is required with the group commitlogSync",
+		},
+		{
+			name: "Durability settings overridden by config",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					Durability: &DurabilityConfig{
+						MaxHintWindow: "1h",
+					},
+					Config: []byte(`{"cassandra-yaml":{"max_hint_window_in_ms":60000}}`),
+				},
+			},
+			want:      `{"cassandra-yaml":{"max_hint_window_in_ms":60000},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
+		{
+			name: "Request timeouts overridden by config",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					RequestTimeouts: &RequestTimeoutsConfig{
+						ReadMs:  10000,
+						WriteMs: 5000,
+					},
+					Config: []byte(`{"cassandra-yaml":{"write_request_timeout_in_ms":3000}}`),
+				},
+			},
+			want:      `{"cassandra-yaml":{"read_request_timeout_in_ms":10000,"write_request_timeout_in_ms":3000},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
+		{
+			name: "Memtable and cache sizes overridden by config",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					MemtablesAndCaches: &MemtablesAndCachesConfig{
+						MemtableHeapSpaceMb: &memtableHeapSpace,
+						RowCacheSizeMb:      &rowCacheSize,
+					},
+					Config: []byte(`{"cassandra-yaml":{"memtable_heap_space_in_mb":1024}}`),
+				},
+			},
+			want:      `{"cassandra-yaml":{"memtable_heap_space_in_mb":1024,"row_cache_size_in_mb":0},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
+		{
+			name: "Prometheus port overridden by config",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName:      "exampleCluster",
+					EnablePrometheus: true,
+					PrometheusPort:   9600,
+					Config:           []byte(`{"10-write-prom-conf":{"port":9500}}`),
+				},
+			},
+			want:      `{"10-write-prom-conf":{"enabled":true,"port":9500},"cassandra-yaml":{},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
+		{
+			name: "GC logging with Cassandra 3.11 and additional jvm opts",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName:     "exampleCluster",
+					ServerType:      "cassandra",
+					ServerVersion:   "3.11.7",
+					EnableGCLogging: true,
+					Config:          []byte(`{"jvm-options":{"additional-jvm-opts":["-Dcassandra.ring_delay_ms=0"]}}`),
+				},
+			},
+			want:      `{"cassandra-yaml":{},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0},"jvm-options":{"additional-jvm-opts":["-Xloggc:/var/log/cassandra/gc.log","-XX:+PrintGCDetails","-XX:+PrintGCDateStamps","-XX:+PrintGCApplicationStoppedTime","-XX:+UseGCLogFileRotation","-XX:NumberOfGCLogFiles=10","-XX:GCLogFileSize=10M","-Dcassandra.ring_delay_ms=0"]}}`,
+			errString: "",
+		},
+		{
+			name: "GC logging with Cassandra 4.0 and a log path",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName:     "exampleCluster",
+					ServerType:      "cassandra",
+					ServerVersion:   "4.0.0",
+					EnableGCLogging: true,
+					GCLogPath:       "/var/lib/cassandra/logs/gc.log",
+				},
+			},
+			want:      `{"cassandra-yaml":{},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0},"jvm11-server-options":{"additional-jvm-opts":["-Xlog:gc=info,heap*=trace,age*=debug,safepoint=info,promotion*=trace:file=/var/lib/cassandra/logs/gc.log:time,uptime,pid,tid,level:filecount=10,filesize=10485760"]},"jvm8-server-options":{"additional-jvm-opts":["-Xloggc:/var/lib/cassandra/logs/gc.log","-XX:+PrintGCDetails","-XX:+PrintGCDateStamps","-XX:+PrintGCApplicationStoppedTime","-XX:+UseGCLogFileRotation","-XX:NumberOfGCLogFiles=10","-XX:GCLogFileSize=10M"]}}`,
+			errString: "",
+		},
+		{
+			name: "Listen interface",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "exampleCluster",
+					Networking: &NetworkingConfig{
+						HostNetwork:               true,
+						ListenInterface:           "bond0",
+						ListenInterfacePreferIPv6: true,
+					},
+				},
+			},
+			want:      `{"cassandra-yaml":{"listen_interface":"bond0","listen_interface_prefer_ipv6":true},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
+		{
+			name: "Rpc settings with Cassandra 3.11",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName:   "exampleCluster",
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Config:        []byte(`{"cassandra-yaml":{"start_rpc":true,"rpc_port":9160,"rpc_keepalive":true}}`),
+				},
+			},
+			want:      `{"cassandra-yaml":{"rpc_keepalive":true,"rpc_port":9160,"start_rpc":true},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
+		{
+			name: "Rpc settings stripped with Cassandra 4.0",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName:   "exampleCluster",
+					ServerType:    "cassandra",
+					ServerVersion: "4.0.0",
+					Config:        []byte(`{"cassandra-yaml":{"start_rpc":true,"rpc_port":9160,"rpc_keepalive":true}}`),
+				},
+			},
+			want:      `{"cassandra-yaml":{"rpc_keepalive":true},"cluster-info":{"name":"exampleCluster","seeds":"exampleCluster-seed-service"},"datacenter-info":{"graph-enabled":0,"name":"exampleDC","solr-enabled":0,"spark-enabled":0}}`,
+			errString: "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -119,89 +468,705 @@ func Test_GenerateBaseConfigString(t *testing.T) {
 	}
 }
 
-func TestCassandraDatacenter_GetContainerPorts(t *testing.T) {
-	type fields struct {
-		TypeMeta   metav1.TypeMeta
-		ObjectMeta metav1.ObjectMeta
-		Spec       CassandraDatacenterSpec
-		Status     CassandraDatacenterStatus
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		want    []corev1.ContainerPort
-		wantErr bool
-	}{
-		{
-			name: "Cassandra 3.11.6",
-			fields: fields{
-				Spec: CassandraDatacenterSpec{
-					ClusterName:   "exampleCluster",
-					ServerType:    "cassandra",
-					ServerVersion: "3.11.6",
-				},
-			},
-			want: []corev1.ContainerPort{
-				{
-					Name:          "native",
-					ContainerPort: DefaultNativePort,
-				}, {
-					Name:          "tls-native",
-					ContainerPort: 9142,
-				}, {
-					Name:          "internode",
-					ContainerPort: DefaultInternodePort,
-				}, {
-					Name:          "tls-internode",
-					ContainerPort: 7001,
-				}, {
-					Name:          "jmx",
-					ContainerPort: 7199,
-				}, {
-					Name:          "mgmt-api-http",
-					ContainerPort: 8080,
-				}, {
-					Name:          "prometheus",
-					ContainerPort: 9103,
-				}, {
-					Name:          "thrift",
-					ContainerPort: 9160,
+func TestCassandraDatacenter_GetContainerPorts(t *testing.T) {
+	type fields struct {
+		TypeMeta   metav1.TypeMeta
+		ObjectMeta metav1.ObjectMeta
+		Spec       CassandraDatacenterSpec
+		Status     CassandraDatacenterStatus
+	}
+	tests := []struct {
+		name    string
+		fields  fields
+		want    []corev1.ContainerPort
+		wantErr bool
+	}{
+		{
+			name: "Cassandra 3.11.6",
+			fields: fields{
+				Spec: CassandraDatacenterSpec{
+					ClusterName:   "exampleCluster",
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.6",
+				},
+			},
+			want: []corev1.ContainerPort{
+				{
+					Name:          "native",
+					ContainerPort: DefaultNativePort,
+				}, {
+					Name:          "tls-native",
+					ContainerPort: 9142,
+				}, {
+					Name:          "internode",
+					ContainerPort: DefaultInternodePort,
+				}, {
+					Name:          "tls-internode",
+					ContainerPort: 7001,
+				}, {
+					Name:          "jmx",
+					ContainerPort: 7199,
+				}, {
+					Name:          "mgmt-api-http",
+					ContainerPort: 8080,
+				}, {
+					Name:          "prometheus",
+					ContainerPort: 9103,
+				}, {
+					Name:          "thrift",
+					ContainerPort: 9160,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Prometheus port conflicting with native port",
+			fields: fields{
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Config:        []byte(`{"10-write-prom-conf":{"enabled":true,"port":9042}}`),
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Storage ports from config",
+			fields: fields{
+				Spec: CassandraDatacenterSpec{
+					ClusterName:   "exampleCluster",
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Config:        []byte(`{"cassandra-yaml":{"storage_port":7010,"ssl_storage_port":7011}}`),
+				},
+			},
+			want: []corev1.ContainerPort{
+				{
+					Name:          "native",
+					ContainerPort: DefaultNativePort,
+				}, {
+					Name:          "tls-native",
+					ContainerPort: 9142,
+				}, {
+					Name:          "internode",
+					ContainerPort: 7010,
+				}, {
+					Name:          "tls-internode",
+					ContainerPort: 7011,
+				}, {
+					Name:          "jmx",
+					ContainerPort: 7199,
+				}, {
+					Name:          "mgmt-api-http",
+					ContainerPort: 8080,
+				}, {
+					Name:          "prometheus",
+					ContainerPort: 9103,
+				}, {
+					Name:          "thrift",
+					ContainerPort: 9160,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "Storage port conflicting with jmx port",
+			fields: fields{
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Config:        []byte(`{"cassandra-yaml":{"storage_port":7199}}`),
+				},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := &CassandraDatacenter{
+				TypeMeta:   tt.fields.TypeMeta,
+				ObjectMeta: tt.fields.ObjectMeta,
+				Spec:       tt.fields.Spec,
+				Status:     tt.fields.Status,
+			}
+			got, err := dc.GetContainerPorts()
+			if tt.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestCassandraDatacenter_GetStoragePorts(t *testing.T) {
+	dc := &CassandraDatacenter{}
+	storagePort, sslStoragePort := dc.GetStoragePorts()
+	assert.Equal(t, DefaultInternodePort, storagePort)
+	assert.Equal(t, DefaultInternodeSSLPort, sslStoragePort)
+
+	dc.Spec.Config = []byte(`{"cassandra-yaml":{"ssl_storage_port":7011}}`)
+	storagePort, sslStoragePort = dc.GetStoragePorts()
+	assert.Equal(t, DefaultInternodePort, storagePort)
+	assert.Equal(t, 7011, sslStoragePort)
+
+	dc.Spec.Config = nil
+	dc.Spec.Networking = &NetworkingConfig{
+		NodePort: &NodePortConfig{Internode: 30700},
+	}
+	storagePort, _ = dc.GetStoragePorts()
+	assert.Equal(t, 30700, storagePort)
+}
+
+func TestCassandraDatacenter_GetClientNativePort(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+		port   int
+		tls    bool
+	}{
+		{"no encryption", ``, 9042, false},
+		{"encryption disabled", `{"cassandra-yaml":{"client_encryption_options":{"enabled":false}}}`, 9042, false},
+		{"encryption on the native port", `{"cassandra-yaml":{"client_encryption_options":{"enabled":true}}}`, 9042, true},
+		{"optional encryption", `{"cassandra-yaml":{"native_transport_port":9043,"client_encryption_options":{"enabled":true,"optional":true}}}`, 9043, false},
+		{"encryption on the ssl port", `{"cassandra-yaml":{"native_transport_port_ssl":9142,"client_encryption_options":{"enabled":true}}}`, 9142, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := &CassandraDatacenter{}
+			if tt.config != "" {
+				dc.Spec.Config = []byte(tt.config)
+			}
+			port, tls, err := dc.GetClientNativePort()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.port, port)
+			assert.Equal(t, tt.tls, tls)
+		})
+	}
+}
+
+func TestCassandraDatacenter_GetCQLConnectionInfo(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc1",
+			Namespace: "test-ns",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName:         "cluster1",
+			SuperuserSecretName: "my-superuser",
+			Config:              []byte(`{"cassandra-yaml":{"native_transport_port_ssl":9142,"client_encryption_options":{"enabled":true}}}`),
+		},
+	}
+
+	info, err := dc.GetCQLConnectionInfo()
+	assert.NoError(t, err)
+	assert.Equal(t, CQLConnectionInfo{
+		Host:            "cluster1-dc1-service.test-ns.svc",
+		Port:            9142,
+		TLS:             true,
+		SuperuserSecret: types.NamespacedName{Name: "my-superuser", Namespace: "test-ns"},
+		UsernameKey:     "username",
+		PasswordKey:     "password",
+	}, info)
+}
+
+func TestCassandraDatacenter_GetPrometheusPort(t *testing.T) {
+	dc := &CassandraDatacenter{}
+	assert.Equal(t, DefaultPrometheusPort, dc.GetPrometheusPort())
+
+	dc.Spec.Config = []byte(`{"10-write-prom-conf":{"enabled":true,"port":9500}}`)
+	assert.Equal(t, 9500, dc.GetPrometheusPort())
+
+	dc.Spec.Config = []byte(`{"10-write-prom-conf":{"enabled":true,"port":"9500"}}`)
+	assert.Equal(t, DefaultPrometheusPort, dc.GetPrometheusPort())
+
+	dc.Spec.Config = nil
+	dc.Spec.EnablePrometheus = true
+	dc.Spec.PrometheusPort = 9600
+	assert.Equal(t, 9600, dc.GetPrometheusPort())
+
+	dc.Spec.Config = []byte(`{"10-write-prom-conf":{"port":9500}}`)
+	assert.Equal(t, 9500, dc.GetPrometheusPort())
+}
+
+func TestCassandraDatacenter_GetServerImageBestEffort(t *testing.T) {
+	dc := &CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			ServerType:    "cassandra",
+			ServerVersion: "2.2.0",
+		},
+	}
+	image, resolved := dc.GetServerImageBestEffort()
+	assert.False(t, resolved)
+	assert.Equal(t, "datastax/cassandra-mgmtapi:2.2.0", image)
+
+	dc.Spec.ServerImage = "example/cassandra:2.2.0"
+	image, resolved = dc.GetServerImageBestEffort()
+	assert.True(t, resolved)
+	assert.Equal(t, "example/cassandra:2.2.0", image)
+}
+
+func TestCassandraDatacenter_GetManagedSeedServiceOverlaps(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc1",
+			Namespace: "ns1",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName: "cluster1",
+			AdditionalSeeds: []string{
+				"10.0.0.1",
+				"cluster1-seed-service",
+				"cluster1-dc1-all-pods-service.ns1.svc",
+				"cluster1-seed-service.ns2.svc.cluster.local",
+				"cluster1-seed-service.ns1.svcs.example.com",
+			},
+		},
+	}
+
+	assert.Equal(t, map[string]string{
+		"cluster1-seed-service":                 "cluster1-seed-service",
+		"cluster1-dc1-all-pods-service.ns1.svc": "cluster1-dc1-all-pods-service",
+	}, dc.GetManagedSeedServiceOverlaps())
+}
+
+func TestCassandraDatacenter_NewSeedService(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc1",
+			Namespace: "ns1",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName: "cluster1",
+			AdditionalServiceConfig: ServiceConfig{
+				SeedService: ServiceConfigAdditions{
+					Labels:      map[string]string{"team": "storage"},
+					Annotations: map[string]string{"example.com/owner": "storage"},
+				},
+			},
+		},
+	}
+
+	service := dc.NewSeedService(true)
+	assert.Equal(t, "cluster1-seed-service", service.Name)
+	assert.Equal(t, "ns1", service.Namespace)
+	assert.Equal(t, map[string]string{
+		ClusterLabel:                   "cluster1",
+		"app.kubernetes.io/managed-by": "cass-operator",
+		"team":                         "storage",
+	}, service.Labels)
+	assert.Equal(t, map[string]string{"example.com/owner": "storage"}, service.Annotations)
+	assert.Equal(t, corev1.ServiceSpec{
+		Selector: map[string]string{
+			ClusterLabel:  "cluster1",
+			SeedNodeLabel: "true",
+		},
+		Type:                     corev1.ServiceTypeClusterIP,
+		ClusterIP:                corev1.ClusterIPNone,
+		PublishNotReadyAddresses: true,
+	}, service.Spec)
+}
+
+func TestCassandraDatacenter_GetUnsupportedStructuredConfigFields(t *testing.T) {
+	rowCacheSize := int64(0)
+	dc := &CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			ServerType:      "cassandra",
+			ServerVersion:   "4.0.0",
+			LogLevel:        "DEBUG",
+			DcSuffix:        "_east",
+			EnableGCLogging: true,
+			RequestTimeouts: &RequestTimeoutsConfig{
+				ReadMs: 10000,
+			},
+			MemtablesAndCaches: &MemtablesAndCachesConfig{
+				RowCacheSizeMb: &rowCacheSize,
+			},
+		},
+	}
+
+	unsupported, err := dc.GetUnsupportedStructuredConfigFields()
+	assert.NoError(t, err)
+	assert.Empty(t, unsupported)
+
+	paths, err := dc.getStructuredConfigPaths()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"jvm11-server-options.additional-jvm-opts", "jvm8-server-options.additional-jvm-opts"}, paths["enableGCLogging"])
+	assert.Equal(t, []string{"cassandra-yaml.read_request_timeout_in_ms"}, paths["requestTimeouts"])
+
+	assert.False(t, dc.isConfigPathSupported("cassandra-yaml.rpc_port"))
+	assert.False(t, dc.isConfigPathSupported("jvm-options.additional-jvm-opts"))
+	assert.True(t, dc.isConfigPathSupported("cassandra-yaml.read_request_timeout_in_ms"))
+
+	dc.Spec.ServerVersion = "3.11.7"
+	assert.True(t, dc.isConfigPathSupported("cassandra-yaml.rpc_port"))
+	assert.False(t, dc.isConfigPathSupported("jvm8-server-options.additional-jvm-opts"))
+
+	dc.Spec.Durability = &DurabilityConfig{CommitlogSync: "group", CommitlogSyncGroupWindow: "15ms"}
+	unsupported, err = dc.GetUnsupportedStructuredConfigFields()
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{"durability": {"cassandra-yaml.commitlog_sync_group_window_in_ms"}}, unsupported)
+
+	dc.Spec.ServerVersion = "4.0.0"
+	unsupported, err = dc.GetUnsupportedStructuredConfigFields()
+	assert.NoError(t, err)
+	assert.Empty(t, unsupported)
+
+	dc.Spec.ServerType = "dse"
+	dc.Spec.ServerVersion = "6.8.4"
+	assert.False(t, dc.isConfigPathSupported("cassandra-yaml.commitlog_sync_group_window_in_ms"))
+
+	dc.Spec.Durability = &DurabilityConfig{MaxHintWindow: "soon"}
+	_, err = dc.GetUnsupportedStructuredConfigFields()
+	assert.Error(t, err)
+}
+
+func TestCassandraDatacenter_NewSiblingDatacenter(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc1",
+			Namespace: "cass",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName:   "cluster1",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			Size:          3,
+			Racks:         []Rack{{Name: "r1"}},
+			ManagementApiAuth: ManagementApiAuthConfig{
+				Insecure: &ManagementApiAuthInsecureConfig{},
+			},
+		},
+	}
+
+	sibling := dc.NewSiblingDatacenter("dc2")
+	assert.Equal(t, "dc2", sibling.Name)
+	assert.Equal(t, "cass", sibling.Namespace)
+	assert.Equal(t, "cluster1", sibling.Spec.ClusterName)
+	assert.Equal(t, "cluster1-superuser", sibling.Spec.SuperuserSecretName)
+	assert.Equal(t, "cassandra", sibling.Spec.ServerType)
+	assert.Equal(t, "3.11.7", sibling.Spec.ServerVersion)
+	assert.NotNil(t, sibling.Spec.ManagementApiAuth.Insecure)
+	assert.Equal(t, int32(0), sibling.Spec.Size)
+	assert.Empty(t, sibling.Spec.Racks)
+}
+
+func TestCassandraDatacenter_IsRollingRestartInProgress(t *testing.T) {
+	lastRestart := metav1.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	makePod := func(created metav1.Time) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created},
+		}
+	}
+	restartedPods := []*corev1.Pod{
+		makePod(metav1.NewTime(lastRestart.Add(time.Minute))),
+		makePod(metav1.NewTime(lastRestart.Add(2 * time.Minute))),
+	}
+
+	t.Run("just requested", func(t *testing.T) {
+		dc := &CassandraDatacenter{}
+		dc.Spec.RollingRestartRequested = true
+		dc.Status.LastRollingRestart = metav1.Unix(1, 0)
+		assert.True(t, dc.IsRollingRestartInProgress(restartedPods))
+	})
+
+	t.Run("in progress", func(t *testing.T) {
+		dc := &CassandraDatacenter{}
+		dc.Status.LastRollingRestart = lastRestart
+		pods := append([]*corev1.Pod{makePod(metav1.NewTime(lastRestart.Add(-time.Hour)))}, restartedPods...)
+		assert.True(t, dc.PodNeedsRollingRestart(pods[0]))
+		assert.True(t, dc.IsRollingRestartInProgress(pods))
+
+		dc.SetCondition(*NewDatacenterCondition(DatacenterRollingRestart, corev1.ConditionTrue))
+		assert.True(t, dc.IsRollingRestartInProgress(restartedPods))
+	})
+
+	t.Run("completed", func(t *testing.T) {
+		dc := &CassandraDatacenter{}
+		dc.Status.LastRollingRestart = lastRestart
+		dc.SetCondition(*NewDatacenterCondition(DatacenterRollingRestart, corev1.ConditionFalse))
+		assert.False(t, dc.PodNeedsRollingRestart(restartedPods[0]))
+		assert.False(t, dc.IsRollingRestartInProgress(restartedPods))
+	})
+}
+
+func TestCassandraDatacenter_SuperuserSecretNeedsUpsert(t *testing.T) {
+	created := metav1.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	upserted := metav1.NewTime(created.Add(time.Hour))
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{CreationTimestamp: created},
+	}
+
+	dc := &CassandraDatacenter{}
+	dc.Status.SuperUserUpserted = metav1.Unix(1, 0)
+	assert.True(t, dc.SuperuserSecretNeedsUpsert(secret), "never upserted")
+
+	dc.Status.SuperUserUpserted = upserted
+	assert.False(t, dc.SuperuserSecretNeedsUpsert(secret), "unchanged since the upsert")
+
+	rotated := metav1.NewTime(upserted.Add(time.Minute))
+	secret.ManagedFields = []metav1.ManagedFieldsEntry{
+		{Manager: "kubectl", Operation: metav1.ManagedFieldsOperationUpdate, Time: &rotated},
+	}
+	assert.True(t, dc.SuperuserSecretNeedsUpsert(secret), "changed after the upsert")
+}
+
+func TestCassandraDatacenter_HasBootstrapped(t *testing.T) {
+	dc := &CassandraDatacenter{}
+	assert.False(t, dc.HasBootstrapped(), "new datacenter")
+
+	dc.Status.LastServerNodeStarted = metav1.Unix(1, 0)
+	dc.Status.SuperUserUpserted = metav1.Unix(1, 0)
+	assert.False(t, dc.HasBootstrapped(), "placeholder times")
+
+	dc.Status.LastServerNodeStarted = metav1.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)
+	assert.False(t, dc.HasBootstrapped(), "started without superuser")
+
+	dc.Status.SuperUserUpserted = metav1.Date(2021, 3, 1, 12, 5, 0, 0, time.UTC)
+	assert.True(t, dc.HasBootstrapped(), "started with superuser")
+
+	dc.Spec.Stopped = true
+	assert.True(t, dc.HasBootstrapped(), "stopped after bootstrap")
+}
+
+func TestCassandraDatacenter_GetSeedServiceName(t *testing.T) {
+	dc := &CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			ClusterName: "bob",
+		},
+	}
+	want := "bob-seed-service"
+	got := dc.GetSeedServiceName()
+
+	if want != got {
+		t.Errorf("CassandraDatacenter.GetSeedService() = %v, want %v", got, want)
+	}
+}
+
+func TestCassandraDatacenter_GetStatefulSetServiceName(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName: "bob",
+		},
+	}
+	assert.Equal(t, "bob-dc1-all-pods-service", dc.GetStatefulSetServiceName())
+}
+
+func TestCassandraDatacenter_GetRemovedRacks(t *testing.T) {
+	oldDc := &CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			Racks: []Rack{{Name: "rack1"}, {Name: "rack2"}, {Name: "rack3"}},
+		},
+	}
+	dc := &CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			Racks: []Rack{{Name: "rack2"}},
+		},
+	}
+	assert.Equal(t, []string{"rack1", "rack3"}, dc.GetRemovedRacks(oldDc))
+	assert.Equal(t, []string{}, oldDc.GetRemovedRacks(dc))
+}
+
+func TestCassandraDatacenter_ValidateConfigStructure(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "exampleDC",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName:   "exampleCluster",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			Config:        []byte(`{"cassandra-yaml":{"num_tokens":8},"jvm-options":{"max_heap_size":"2G"},"logback-xml":"DEBUG","dse-yaml":{}}`),
+		},
+	}
+	issues, err := dc.ValidateConfigStructure()
+	assert.NoError(t, err)
+	assert.Equal(t, []string{
+		"config section dse-yaml is not a config file of cassandra 3.11.7",
+		"config section logback-xml is not an object",
+	}, issues)
+
+	dc.Spec.ServerVersion = "4.0.0"
+	dc.Spec.Config = []byte(`{"cassandra-yaml":{"num_tokens":8},"jvm-server-options":{"initial_heap_size":"2G"}}`)
+	issues, err = dc.ValidateConfigStructure()
+	assert.NoError(t, err)
+	assert.Empty(t, issues)
+
+	dc.Spec.Config = []byte(`{"cassandra-yaml":`)
+	_, err = dc.ValidateConfigStructure()
+	assert.Error(t, err)
+}
+
+func TestCassandraDatacenter_GetImagePullPolicies(t *testing.T) {
+	dc := &CassandraDatacenter{}
+	assert.Equal(t, corev1.PullIfNotPresent, dc.GetServerImagePullPolicy())
+	assert.Equal(t, corev1.PullIfNotPresent, dc.GetConfigBuilderImagePullPolicy())
+
+	dc.Spec.ServerImage = "datastax/cassandra-mgmtapi-3_11_7@sha256:e4a2b0bb13c7b7b9d4c5c2ddf7d0d6d5e3c6b0a3a9a1c0c6c4d2f8b5e6a7d8c9"
+	dc.Spec.ConfigBuilderImage = "datastax/cass-config-builder:latest"
+	assert.Equal(t, corev1.PullIfNotPresent, dc.GetServerImagePullPolicy())
+	assert.Equal(t, corev1.PullAlways, dc.GetConfigBuilderImagePullPolicy())
+
+	dc.Spec.ServerImagePullPolicy = corev1.PullNever
+	dc.Spec.ConfigBuilderImagePullPolicy = corev1.PullIfNotPresent
+	assert.Equal(t, corev1.PullNever, dc.GetServerImagePullPolicy())
+	assert.Equal(t, corev1.PullIfNotPresent, dc.GetConfigBuilderImagePullPolicy())
+}
+
+func TestCassandraDatacenter_HasOperatorFinalizer(t *testing.T) {
+	dc := &CassandraDatacenter{}
+	assert.False(t, dc.HasOperatorFinalizer())
+
+	dc.SetFinalizers([]string{"other.example.com"})
+	assert.False(t, dc.HasOperatorFinalizer())
+
+	dc.SetFinalizers(append(dc.GetFinalizers(), GetOperatorFinalizers()...))
+	assert.True(t, dc.HasOperatorFinalizer())
+	assert.Equal(t, []string{"other.example.com", "finalizer.cassandra.datastax.com"}, dc.GetFinalizers())
+}
+
+func TestCassandraDatacenter_GetPodLabelsDelta(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName: "cluster1",
+			PodTemplateSpec: &corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						"team":        "storage",
+						RackLabel:     "user-rack",
+						"tier":        "db",
+						CassNodeState: "Started",
+					},
 				},
 			},
-			wantErr: false,
 		},
 	}
+	current := map[string]string{
+		ClusterLabel:                   "cluster1",
+		DatacenterLabel:                "dc1",
+		RackLabel:                      "r1",
+		oplabels.ManagedByLabel:        oplabels.ManagedByLabelValue,
+		CassNodeState:                  "Starting",
+		"tier":                         "cache",
+		"cassandra.datastax.com/other": "kept",
+	}
+
+	assert.Equal(t, map[string]string{
+		"team": "storage",
+		"tier": "db",
+	}, dc.GetPodLabelsDelta("r1", current))
+}
+
+func TestCassandraDatacenter_GetSeedServicePublishNotReadyAddresses(t *testing.T) {
+	dc := &CassandraDatacenter{}
+	assert.True(t, dc.GetSeedServicePublishNotReadyAddresses(), "not-ready seeds should be published while the datacenter is forming")
+
+	dc.Status.CassandraOperatorProgress = ProgressReady
+	assert.False(t, dc.GetSeedServicePublishNotReadyAddresses(), "not-ready seeds should not be published once the datacenter is ready")
+
+	dc.Spec.AlwaysPublishNotReadySeeds = true
+	assert.True(t, dc.GetSeedServicePublishNotReadyAddresses(), "not-ready seeds should always be published when requested")
+}
+
+func TestCassandraDatacenter_ValidateSuperuserConfig(t *testing.T) {
+	tests := []struct {
+		name                string
+		superuserSecretName string
+		users               []CassandraUser
+		errString           string
+	}{
+		{"generated superuser", "", []CassandraUser{{SecretName: "app-user"}}, ""},
+		{"explicit superuser", "my-superuser", []CassandraUser{{SecretName: "cluster1-superuser", Superuser: true}}, ""},
+		{"invalid secret name", "My_Superuser", nil, "superuserSecretName 'My_Superuser' is not a valid secret name"},
+		{"generated superuser in users", "", []CassandraUser{{SecretName: "cluster1-superuser"}}, "users lists the generated superuser secret cluster1-superuser, which the operator already upserts"},
+		{"explicit superuser in users", "my-superuser", []CassandraUser{{SecretName: "my-superuser", Superuser: true}}, "users lists the superuserSecretName secret my-superuser, which the operator already upserts"},
+		{"same user listed twice", "", []CassandraUser{{SecretName: "app-user"}, {SecretName: "app-user"}}, ""},
+		{"conflicting superuser flags", "", []CassandraUser{{SecretName: "app-user"}, {SecretName: "app-user", Superuser: true}}, "users lists the secret app-user both as a superuser and as a regular user"},
+	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			dc := &CassandraDatacenter{
-				TypeMeta:   tt.fields.TypeMeta,
-				ObjectMeta: tt.fields.ObjectMeta,
-				Spec:       tt.fields.Spec,
-				Status:     tt.fields.Status,
+				Spec: CassandraDatacenterSpec{
+					ClusterName:         "cluster1",
+					SuperuserSecretName: tt.superuserSecretName,
+					Users:               tt.users,
+				},
 			}
-			got, err := dc.GetContainerPorts()
-			if tt.wantErr {
-				assert.Error(t, err)
-			} else {
+			err := dc.ValidateSuperuserConfig()
+			if tt.errString == "" {
 				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.errString)
 			}
-			assert.Equal(t, tt.want, got)
 		})
 	}
 }
 
-func TestCassandraDatacenter_GetSeedServiceName(t *testing.T) {
+func TestCassandraDatacenter_GetRacksSharingZones(t *testing.T) {
 	dc := &CassandraDatacenter{
 		Spec: CassandraDatacenterSpec{
-			ClusterName: "bob",
+			Racks: []Rack{
+				{Name: "r1", Zone: "zone-a"},
+				{Name: "r2", NodeAffinityLabels: map[string]string{corev1.LabelZoneFailureDomainStable: "zone-a"}},
+				{Name: "r3", Zone: "zone-b"},
+				{Name: "r4"},
+				{Name: "r5"},
+			},
 		},
 	}
-	want := "bob-seed-service"
-	got := dc.GetSeedServiceName()
+	assert.Equal(t, map[string][]string{"zone-a": {"r1", "r2"}}, dc.GetRacksSharingZones())
 
-	if want != got {
-		t.Errorf("CassandraDatacenter.GetSeedService() = %v, want %v", got, want)
+	dc.Spec.NodeAffinityLabels = map[string]string{corev1.LabelZoneFailureDomain: "zone-c"}
+	assert.Equal(t, "zone-c", dc.GetRackZone(dc.Spec.Racks[3]))
+	assert.Equal(t, map[string][]string{
+		"zone-a": {"r1", "r2"},
+		"zone-c": {"r4", "r5"},
+	}, dc.GetRacksSharingZones())
+}
+
+func TestCassandraDatacenter_IsSameCluster(t *testing.T) {
+	makeDc := func(namespace string, name string, clusterName string) *CassandraDatacenter {
+		return &CassandraDatacenter{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+			},
+			Spec: CassandraDatacenterSpec{
+				ClusterName: clusterName,
+			},
+		}
+	}
+
+	dc1 := makeDc("ns1", "dc1", "cluster1")
+	assert.True(t, dc1.IsSameCluster(makeDc("ns1", "dc2", "cluster1")))
+	assert.False(t, dc1.IsSameCluster(makeDc("ns1", "dc2", "cluster2")))
+	assert.False(t, dc1.IsSameCluster(makeDc("ns2", "dc2", "cluster1")), "datacenters in other namespaces do not join the cluster")
+}
+
+func TestCassandraDatacenter_GetNodeReplacementStrategy(t *testing.T) {
+	dc := &CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			ReplaceNodes: []string{"pod-a"},
+		},
+		Status: CassandraDatacenterStatus{
+			NodeReplacements: []string{"pod-b"},
+		},
 	}
+	assert.Equal(t, NodeReplacementReplace, dc.GetNodeReplacementStrategy("pod-a"))
+	assert.Equal(t, NodeReplacementReplace, dc.GetNodeReplacementStrategy("pod-b"))
+	assert.Equal(t, "", dc.GetNodeReplacementStrategy("pod-c"))
+
+	dc.Spec.NodeReplacementStrategy = NodeReplacementRestart
+	assert.Equal(t, NodeReplacementRestart, dc.GetNodeReplacementStrategy("pod-b"))
 }
 
 func TestCassandraDatacenter_SplitRacks_balances_racks_when_no_extra_nodes(t *testing.T) {
@@ -214,6 +1179,34 @@ func TestCassandraDatacenter_SplitRacks_balances_racks_when_some_extra_nodes(t *
 	assert.ElementsMatch(t, rackNodeCounts, []int{3, 3, 3, 2, 2}, "Rack node counts were not balanced")
 }
 
+func TestCassandraDatacenter_SplitRacksWithStrategy(t *testing.T) {
+	racks := []Rack{{Name: "r1"}, {Name: "r2", PreferExtraNodes: true}, {Name: "r3"}, {Name: "r4", PreferExtraNodes: true}}
+	assert.Equal(t, []int{3, 3, 2, 2}, SplitRacksWithStrategy(10, racks, ""))
+	assert.Equal(t, []int{3, 3, 2, 2}, SplitRacksWithStrategy(10, racks, RackDistributionFirstRacks))
+	assert.Equal(t, []int{2, 2, 3, 3}, SplitRacksWithStrategy(10, racks, RackDistributionLastRacks))
+	assert.Equal(t, []int{2, 3, 2, 3}, SplitRacksWithStrategy(10, racks, RackDistributionPreferredRacks))
+	assert.Equal(t, []int{3, 3, 2, 3}, SplitRacksWithStrategy(11, racks, RackDistributionPreferredRacks))
+	assert.Equal(t, []int{2, 2, 2, 2}, SplitRacksWithStrategy(8, racks, RackDistributionLastRacks))
+}
+
+func TestCassandraDatacenter_GetRackSeedCounts(t *testing.T) {
+	dc := &CassandraDatacenter{}
+	assert.Equal(t, []int{2, 1}, dc.GetRackSeedCounts([]int{3, 3}))
+	assert.Equal(t, []int{1, 1, 1, 1}, dc.GetRackSeedCounts([]int{2, 2, 2, 2}))
+	assert.Equal(t, []int{1, 1, 0}, dc.GetRackSeedCounts([]int{1, 1, 0}))
+
+	dc.Spec.SeedsPerRack = 2
+	assert.Equal(t, []int{2, 2, 1, 0}, dc.GetRackSeedCounts([]int{3, 2, 1, 0}))
+}
+
+func TestCassandraDatacenter_GetReconcileInterval(t *testing.T) {
+	dc := &CassandraDatacenter{}
+	assert.Equal(t, time.Duration(0), dc.GetReconcileInterval())
+
+	dc.Spec.ReconcileIntervalSeconds = 300
+	assert.Equal(t, 5*time.Minute, dc.GetReconcileInterval())
+}
+
 func TestCassandraDatacenter_GetRackLabels(t *testing.T) {
 	type args struct {
 		rackName string
@@ -252,3 +1245,388 @@ func TestCassandraDatacenter_GetRackLabels(t *testing.T) {
 		})
 	}
 }
+
+func TestCassandraDatacenter_GetAllPodsServicePublishNotReadyAddresses(t *testing.T) {
+	dc := &CassandraDatacenter{}
+	assert.True(t, dc.GetAllPodsServicePublishNotReadyAddresses())
+
+	publish := false
+	dc.Spec.AllPodsServicePublishNotReadyAddresses = &publish
+	assert.False(t, dc.GetAllPodsServicePublishNotReadyAddresses())
+
+	publish = true
+	assert.True(t, dc.GetAllPodsServicePublishNotReadyAddresses())
+}
+
+func TestCassandraDatacenter_GetRackCanaryPartition(t *testing.T) {
+	dc := &CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			Size:               6,
+			Racks:              []Rack{{Name: "rack1"}, {Name: "rack2"}},
+			CanaryUpgrade:      true,
+			CanaryUpgradeCount: 1,
+		},
+	}
+
+	// the canary rack updates one node, the other rack is paused
+	assert.Equal(t, int32(2), dc.GetRackCanaryPartition(0, 3))
+	assert.Equal(t, int32(3), dc.GetRackCanaryPartition(1, 3))
+
+	dc.Spec.CanaryUpgradeCount = 0
+	assert.Equal(t, int32(0), dc.GetRackCanaryPartition(0, 3))
+	assert.Equal(t, int32(3), dc.GetRackCanaryPartition(1, 3))
+
+	// resumed
+	dc.Spec.CanaryUpgrade = false
+	assert.Equal(t, int32(0), dc.GetRackCanaryPartition(0, 3))
+	assert.Equal(t, int32(0), dc.GetRackCanaryPartition(1, 3))
+}
+
+func TestCassandraDatacenter_GetRackUpdateStrategies(t *testing.T) {
+	int32Ptr := func(i int32) *int32 { return &i }
+
+	tests := []struct {
+		name               string
+		canaryUpgrade      bool
+		canaryUpgradeCount int32
+		want               []RackUpdateStrategy
+	}{
+		{
+			name: "canary upgrade off",
+			want: []RackUpdateStrategy{
+				{RackName: "rack1"},
+				{RackName: "rack2"},
+				{RackName: "rack3"},
+			},
+		},
+		{
+			name:          "canary upgrade without count",
+			canaryUpgrade: true,
+			want: []RackUpdateStrategy{
+				{RackName: "rack1", Partition: int32Ptr(0)},
+				{RackName: "rack2", Paused: true, Partition: int32Ptr(2)},
+				{RackName: "rack3", Paused: true, Partition: int32Ptr(2)},
+			},
+		},
+		{
+			name:               "canary upgrade with count",
+			canaryUpgrade:      true,
+			canaryUpgradeCount: 1,
+			want: []RackUpdateStrategy{
+				{RackName: "rack1", Partition: int32Ptr(2)},
+				{RackName: "rack2", Paused: true, Partition: int32Ptr(2)},
+				{RackName: "rack3", Paused: true, Partition: int32Ptr(2)},
+			},
+		},
+		{
+			name:               "canary upgrade with count larger than rack",
+			canaryUpgrade:      true,
+			canaryUpgradeCount: 5,
+			want: []RackUpdateStrategy{
+				{RackName: "rack1", Partition: int32Ptr(0)},
+				{RackName: "rack2", Paused: true, Partition: int32Ptr(2)},
+				{RackName: "rack3", Paused: true, Partition: int32Ptr(2)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dc := &CassandraDatacenter{
+				Spec: CassandraDatacenterSpec{
+					Size:               7,
+					Racks:              []Rack{{Name: "rack1"}, {Name: "rack2"}, {Name: "rack3"}},
+					CanaryUpgrade:      tt.canaryUpgrade,
+					CanaryUpgradeCount: tt.canaryUpgradeCount,
+				},
+			}
+			assert.Equal(t, tt.want, dc.GetRackUpdateStrategies())
+		})
+	}
+}
+
+func TestCassandraDatacenter_GetPodNames(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName: "cluster1",
+			Size:        3,
+			Racks:       []Rack{{Name: "r1"}, {Name: "r2"}},
+		},
+	}
+
+	assert.Equal(t, []string{
+		"cluster1-dc1-r1-sts-0",
+		"cluster1-dc1-r1-sts-1",
+		"cluster1-dc1-r2-sts-0",
+	}, dc.GetPodNames())
+}
+
+func TestCassandraDatacenter_GroupPodsByRack(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName: "cluster1",
+			Size:        3,
+			Racks:       []Rack{{Name: "r1"}, {Name: "r2"}, {Name: "r3"}},
+		},
+	}
+
+	assert.Equal(t, map[string][]string{
+		"r1": {"cluster1-dc1-r1-sts-0"},
+		"r2": {"cluster1-dc1-r2-sts-0"},
+		"r3": {"cluster1-dc1-r3-sts-0"},
+	}, dc.GetPodNamesByRack())
+
+	makePod := func(name string, rackName string) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   name,
+				Labels: map[string]string{RackLabel: rackName},
+			},
+		}
+	}
+	r1Pod10 := makePod("cluster1-dc1-r1-sts-10", "r1")
+	r1Pod2 := makePod("cluster1-dc1-r1-sts-2", "r1")
+	r2Pod0 := makePod("cluster1-dc1-r2-sts-0", "r2")
+	otherPod := makePod("cluster1-dc1-r9-sts-0", "r9")
+
+	assert.Equal(t, map[string][]*corev1.Pod{
+		"r1": {r1Pod2, r1Pod10},
+		"r2": {r2Pod0},
+		"r3": {},
+	}, dc.GroupPodsByRack([]*corev1.Pod{r1Pod10, otherPod, r2Pod0, r1Pod2}))
+}
+
+func TestCassandraDatacenter_GetExpectedStatefulSets(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName: "cluster1",
+			Size:        5,
+			Racks:       []Rack{{Name: "r1"}, {Name: "r2"}, {Name: "r3"}},
+		},
+	}
+
+	assert.Equal(t, []ExpectedStatefulSet{
+		{Name: "cluster1-dc1-r1-sts", RackName: "r1", NodeCount: 2},
+		{Name: "cluster1-dc1-r2-sts", RackName: "r2", NodeCount: 2},
+		{Name: "cluster1-dc1-r3-sts", RackName: "r3", NodeCount: 1},
+	}, dc.GetExpectedStatefulSets())
+}
+
+func TestCassandraDatacenterStatus_PruneNodeStatuses(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName: "cluster1",
+			Size:        3,
+		},
+		Status: CassandraDatacenterStatus{
+			NodeStatuses: CassandraStatusMap{
+				"cluster1-dc1-default-sts-0": {HostID: "a"},
+				"cluster1-dc1-default-sts-1": {HostID: "b"},
+				"cluster1-dc1-default-sts-2": {HostID: "c"},
+			},
+		},
+	}
+
+	assert.Empty(t, dc.Status.GetStaleNodeStatuses(dc.GetPodNames()))
+
+	// Scale down leaves entries behind for the removed pods
+	dc.Spec.Size = 1
+	assert.Equal(t,
+		[]string{"cluster1-dc1-default-sts-1", "cluster1-dc1-default-sts-2"},
+		dc.Status.GetStaleNodeStatuses(dc.GetPodNames()))
+
+	pruned := dc.Status.PruneNodeStatuses(dc.GetPodNames())
+	assert.Equal(t, []string{"cluster1-dc1-default-sts-1", "cluster1-dc1-default-sts-2"}, pruned)
+	assert.Equal(t, CassandraStatusMap{"cluster1-dc1-default-sts-0": {HostID: "a"}}, dc.Status.NodeStatuses)
+
+	// A replaced node keeps its pod name, so its entry is not stale
+	dc.Status.NodeStatuses["cluster1-dc1-default-sts-0"] = CassandraNodeStatus{HostID: "d"}
+	assert.Empty(t, dc.Status.PruneNodeStatuses(dc.GetPodNames()))
+	assert.Equal(t, CassandraStatusMap{"cluster1-dc1-default-sts-0": {HostID: "d"}}, dc.Status.NodeStatuses)
+}
+
+func TestCassandraDatacenter_GetServiceMonitorSpec(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName: "cluster1",
+		},
+	}
+
+	assert.Nil(t, dc.GetServiceMonitorSpec())
+
+	dc.Spec.Config = []byte(`{"10-write-prom-conf":{"enabled":false}}`)
+	assert.Nil(t, dc.GetServiceMonitorSpec())
+
+	dc.Spec.Config = []byte(`{"10-write-prom-conf":{"enabled":true,"port":9103}}`)
+	assert.Equal(t, &ServiceMonitorSpec{
+		Selector: map[string]string{
+			ClusterLabel:     "cluster1",
+			DatacenterLabel:  "dc1",
+			PromMetricsLabel: "true",
+		},
+		Port: "prometheus",
+		Path: "/metrics",
+	}, dc.GetServiceMonitorSpec())
+
+	dc.Spec.Config = nil
+	dc.Spec.EnablePrometheus = true
+	assert.NotNil(t, dc.GetServiceMonitorSpec())
+
+	dc.Spec.Config = []byte(`{"10-write-prom-conf":{"enabled":false}}`)
+	assert.Nil(t, dc.GetServiceMonitorSpec())
+}
+
+func TestCassandraDatacenter_GetSystemAuthAlterKeyspaceCQL(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+	}
+
+	assert.Equal(t, "", dc.GetSystemAuthAlterKeyspaceCQL())
+
+	dc.Spec.SystemAuthReplication = map[string]int32{"dc2": 5, "dc1": 3}
+	assert.Equal(t,
+		"ALTER KEYSPACE system_auth WITH replication = {'class': 'NetworkTopologyStrategy', 'dc1': 3, 'dc2': 5}",
+		dc.GetSystemAuthAlterKeyspaceCQL())
+	assert.Equal(t, []map[string]string{
+		{"dc_name": "dc1", "replication_factor": "3"},
+		{"dc_name": "dc2", "replication_factor": "5"},
+	}, dc.GetSystemAuthReplicationSettings())
+}
+
+func TestDiffSpecs(t *testing.T) {
+	oldDc := &CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			ClusterName:   "cluster1",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			Size:          3,
+		},
+	}
+
+	newDc := oldDc.DeepCopy()
+	assert.Empty(t, DiffSpecs(oldDc, newDc))
+
+	newDc.Spec.Size = 6
+	newDc.Spec.ServerVersion = "3.11.10"
+	newDc.Spec.RollingRestartRequested = true
+	newDc.Spec.Config = []byte(`{"cassandra-yaml":{"num_tokens":8}}`)
+
+	assert.Equal(t, []SpecFieldChange{
+		{Field: "Size", Impact: StatusOnly},
+		{Field: "ServerVersion", Impact: RequiresRollingUpdate},
+		{Field: "Config", Impact: RequiresRollingUpdate},
+		{Field: "RollingRestartRequested", Impact: Ignored},
+	}, DiffSpecs(oldDc, newDc))
+
+	oldDc.Spec.Racks = []Rack{{Name: "r1"}}
+	newDc = oldDc.DeepCopy()
+	newDc.Spec.Racks = append(newDc.Spec.Racks, Rack{Name: "r2"})
+	assert.Equal(t, []SpecFieldChange{{Field: "Racks", Impact: StatusOnly}}, DiffSpecs(oldDc, newDc))
+
+	newDc.Spec.Racks[0].NodeAffinityLabels = map[string]string{"dedicated": "cassandra"}
+	assert.Equal(t, []SpecFieldChange{{Field: "Racks", Impact: RequiresRollingUpdate}}, DiffSpecs(oldDc, newDc))
+
+	newDc = oldDc.DeepCopy()
+	newDc.Spec.ForceConfigOverride = true
+	newDc.Spec.DisableConfigValidation = true
+	newDc.Spec.StrictConfigValidation = true
+	newDc.Spec.RequireImageTags = true
+	newDc.Spec.DebugConfigBuilder = true
+	changes := DiffSpecs(oldDc, newDc)
+	assert.Len(t, changes, 5)
+	for _, change := range changes {
+		assert.Equal(t, Ignored, change.Impact, change.Field)
+	}
+}
+
+func TestCassandraDatacenter_GetRackResources(t *testing.T) {
+	dcResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("1"),
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+		},
+	}
+	r1Resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("8Gi"),
+		},
+	}
+	r2Resources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("4"),
+			corev1.ResourceMemory: resource.MustParse("16Gi"),
+		},
+	}
+
+	dc := &CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			Resources: dcResources,
+			Racks: []Rack{
+				{Name: "r1", Resources: &r1Resources},
+				{Name: "r2", Resources: &r2Resources},
+				{Name: "r3"},
+			},
+		},
+	}
+
+	assert.Equal(t, r1Resources, dc.GetRackResources("r1"))
+	assert.Equal(t, r2Resources, dc.GetRackResources("r2"))
+	assert.Equal(t, dcResources, dc.GetRackResources("r3"))
+}
+
+func TestCassandraDatacenter_GetConfigHash(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName: "cluster1",
+			Config:      []byte(`{"cassandra-yaml":{"num_tokens":8,"authenticator":"PasswordAuthenticator"}}`),
+		},
+	}
+
+	hash, err := dc.GetConfigHash()
+	assert.NoError(t, err)
+
+	// The same config with a different key order gives the same hash
+	dc.Spec.Config = []byte(`{"cassandra-yaml":{"authenticator":"PasswordAuthenticator","num_tokens":8}}`)
+	sameHash, err := dc.GetConfigHash()
+	assert.NoError(t, err)
+	assert.Equal(t, hash, sameHash)
+
+	dc.Spec.Config = []byte(`{"cassandra-yaml":{"authenticator":"PasswordAuthenticator","num_tokens":16}}`)
+	otherHash, err := dc.GetConfigHash()
+	assert.NoError(t, err)
+	assert.NotEqual(t, hash, otherHash)
+
+	key, value, err := dc.GetConfigHashAnnotation()
+	assert.NoError(t, err)
+	assert.Equal(t, ConfigHashAnnotation, key)
+	assert.Equal(t, otherHash, value)
+
+	// With a config secret the hash is kept in the datacenter annotation
+	dc.Spec.ConfigSecret = "config"
+	_, err = dc.GetConfigHash()
+	assert.Error(t, err)
+	dc.Annotations = map[string]string{ConfigHashAnnotation: "abc"}
+	hash, err = dc.GetConfigHash()
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", hash)
+}
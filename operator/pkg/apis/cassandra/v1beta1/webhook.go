@@ -7,17 +7,31 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"path"
 	"reflect"
+	"sort"
 	"strings"
 
 	"github.com/k8ssandra/cass-operator/operator/pkg/images"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 )
 
 var log = logf.Log.WithName("api")
 
+// writeRejection is the error of a write the webhook rejects, holding what the write
+// attempted to do
+type writeRejection struct {
+	attempted string
+}
+
+func (e *writeRejection) Error() string {
+	return "CassandraDatacenter write rejected, attempted to " + e.attempted
+}
+
 func attemptedTo(action string, actionStrArgs ...interface{}) error {
 	var msg string
 	if actionStrArgs != nil {
@@ -25,28 +39,115 @@ func attemptedTo(action string, actionStrArgs ...interface{}) error {
 	} else {
 		msg = action
 	}
-	return fmt.Errorf("CassandraDatacenter write rejected, attempted to %s", msg)
+	return &writeRejection{attempted: msg}
+}
+
+// ValidationSeverity tells whether a ValidationIssue rejects the datacenter
+type ValidationSeverity string
+
+const (
+	// ValidationSeverityError issues make the webhook reject the datacenter
+	ValidationSeverityError ValidationSeverity = "Error"
+	// ValidationSeverityWarning issues are only logged by the webhook
+	ValidationSeverityWarning ValidationSeverity = "Warning"
+)
+
+// ValidationIssue is a problem of a datacenter found by ValidateStructured
+type ValidationIssue struct {
+	// FieldPath is the path of the field causing the issue, e.g. spec.serverVersion
+	FieldPath string
+	Severity  ValidationSeverity
+	// Message describes the issue. For errors, it is what the rejected write attempted
+	// to do.
+	Message string
+}
+
+// ValidateStructured returns every problem the validating webhook checks the datacenter
+// for, errors first in the order the webhook checks them. The webhook rejects the
+// datacenter with the first error and logs the warnings. Warnings needing the k8s cluster
+// are only returned while WebhookNodeLister and WebhookPodLister are set.
+func (dc *CassandraDatacenter) ValidateStructured() []ValidationIssue {
+	return append(getValidationErrors(*dc), getValidationWarnings(*dc)...)
 }
 
 // ValidateSingleDatacenter checks that no values are improperly set on a CassandraDatacenter
 func ValidateSingleDatacenter(dc CassandraDatacenter) error {
+	return firstValidationError(getValidationErrors(dc))
+}
+
+// firstValidationError returns the rejection of the first error among the issues, or nil
+// if there is none
+func firstValidationError(issues []ValidationIssue) error {
+	for _, issue := range issues {
+		if issue.Severity == ValidationSeverityError {
+			return attemptedTo(issue.Message)
+		}
+	}
+	return nil
+}
+
+// validationIssues collects the issues of a datacenter
+type validationIssues []ValidationIssue
+
+// reject adds an error for the field if err is not nil
+func (issues *validationIssues) reject(fieldPath string, err error) {
+	if err == nil {
+		return
+	}
+	message := err.Error()
+	if rejection, ok := err.(*writeRejection); ok {
+		message = rejection.attempted
+	}
+	*issues = append(*issues, ValidationIssue{
+		FieldPath: fieldPath,
+		Severity:  ValidationSeverityError,
+		Message:   message,
+	})
+}
+
+// warn adds a warning for the field for each message
+func (issues *validationIssues) warn(fieldPath string, messages ...string) {
+	for _, message := range messages {
+		*issues = append(*issues, ValidationIssue{
+			FieldPath: fieldPath,
+			Severity:  ValidationSeverityWarning,
+			Message:   message,
+		})
+	}
+}
+
+// getValidationErrors returns the errors the webhook rejects the datacenter for, in the
+// order it checks them
+func getValidationErrors(dc CassandraDatacenter) []ValidationIssue {
+	issues := validationIssues{}
+
 	// Ensure serverVersion and serverType are compatible
 
 	if dc.Spec.ServerType == "dse" {
 		if !images.IsDseVersionSupported(dc.Spec.ServerVersion) {
-			return attemptedTo("use unsupported DSE version '%s'", dc.Spec.ServerVersion)
+			issues.reject("spec.serverVersion", attemptedTo("use unsupported DSE version '%s'", dc.Spec.ServerVersion))
 		}
 	}
 
 	if dc.Spec.ServerType == "cassandra" && dc.Spec.DseWorkloads != nil {
 		if dc.Spec.DseWorkloads.AnalyticsEnabled || dc.Spec.DseWorkloads.GraphEnabled || dc.Spec.DseWorkloads.SearchEnabled {
-			return attemptedTo("enable DSE workloads if server type is Cassandra")
+			issues.reject("spec.dseWorkloads", attemptedTo("enable DSE workloads if server type is Cassandra"))
 		}
 	}
 
 	if dc.Spec.ServerType == "cassandra" {
 		if !images.IsOssVersionSupported(dc.Spec.ServerVersion) {
-			return attemptedTo("use unsupported Cassandra version '%s'", dc.Spec.ServerVersion)
+			issues.reject("spec.serverVersion", attemptedTo("use unsupported Cassandra version '%s'", dc.Spec.ServerVersion))
+		}
+	}
+
+	if dc.Spec.ServerImage != "" && !images.IsValidImageReference(dc.Spec.ServerImage) {
+		issues.reject("spec.serverImage", attemptedTo("use serverImage '%s' which is not a valid image reference, expected [registry/]repository[:tag][@digest]", dc.Spec.ServerImage))
+	}
+
+	if dc.Spec.RequireImageTags {
+		for _, untagged := range findUntaggedImages(dc) {
+			issues.reject("spec."+untagged.field, attemptedTo("use %s without a tag or digest with requireImageTags", untagged))
 		}
 	}
 
@@ -63,29 +164,634 @@ func ValidateSingleDatacenter(dc CassandraDatacenter) error {
 
 	serverStr := fmt.Sprintf("%s-%s", dc.Spec.ServerType, dc.Spec.ServerVersion)
 	if hasJvmOptions && (isDse || isCassandra4) {
-		return attemptedTo("define config jvm-options with %s", serverStr)
+		issues.reject("spec.config", attemptedTo("define config jvm-options with %s", serverStr))
 	}
 	if hasJvmServerOptions && isCassandra3 {
-		return attemptedTo("define config jvm-server-options with %s", serverStr)
+		issues.reject("spec.config", attemptedTo("define config jvm-server-options with %s", serverStr))
 	}
 	if hasDseYaml && (isCassandra3 || isCassandra4) {
-		return attemptedTo("define config dse-yaml with %s", serverStr)
+		issues.reject("spec.config", attemptedTo("define config dse-yaml with %s", serverStr))
+	}
+
+	if len(dc.Spec.Config) > 0 && !dc.AllowsReservedConfigKeys() {
+		cassandraYaml, _ := c["cassandra-yaml"].(map[string]interface{})
+		if seedProvider, ok := cassandraYaml["seed_provider"]; ok && containsKey(seedProvider, "seeds") {
+			issues.reject("spec.config", attemptedTo("set seeds through cassandra-yaml seed_provider in config, use additionalSeeds instead"))
+		}
+
+		reserved, err := GetReservedConfigKeys(dc.Spec.Config)
+		if err == nil && len(reserved) > 0 {
+			issues.reject("spec.config", attemptedTo("set reserved config keys %s without forceConfigOverride", strings.Join(reserved, ", ")))
+		}
+	}
+
+	if dc.Spec.StrictConfigValidation && dc.IsConfigValidationEnabled() {
+		configIssues, err := dc.ValidateConfigStructure()
+		if err != nil {
+			issues.reject("spec.config", attemptedTo("use config which cannot be rendered, %s", err.Error()))
+		} else if len(configIssues) > 0 {
+			issues.reject("spec.config", attemptedTo("use config the config builder would reject, %s", strings.Join(configIssues, "; ")))
+		}
+	}
+
+	if _, err := dc.GetDurabilityConfigValues(); err != nil {
+		issues.reject("spec.durability", attemptedTo("use invalid durability settings, %s", err.Error()))
+	}
+
+	if _, err := dc.GetRequestTimeoutConfigValues(); err != nil {
+		issues.reject("spec.requestTimeouts", attemptedTo("use invalid request timeouts, %s", err.Error()))
+	}
+
+	if _, err := dc.GetMemtablesAndCachesConfigValues(); err != nil {
+		issues.reject("spec.memtablesAndCaches", attemptedTo("use invalid memtable and cache sizes, %s", err.Error()))
+	}
+
+	if _, err := dc.GetListenInterfaceConfigValues(); err != nil {
+		issues.reject("spec.networking.listenInterface", attemptedTo("use an invalid listen interface, %s", err.Error()))
+	}
+
+	// Fields which cannot be rendered are rejected above
+	if unsupported, err := dc.GetUnsupportedStructuredConfigFields(); err == nil {
+		fields := make([]string, 0, len(unsupported))
+		for field := range unsupported {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+		for _, field := range fields {
+			issues.reject("spec."+field, attemptedTo("use %s with %s %s, which does not support %s",
+				field, dc.Spec.ServerType, dc.Spec.ServerVersion, strings.Join(unsupported[field], ", ")))
+		}
+	}
+
+	issues.reject("spec.stopped", validateStoppedFlags(dc))
+
+	if dc.Spec.SeedsPerRack < 0 {
+		issues.reject("spec.seedsPerRack", attemptedTo("use seedsPerRack %d, at least one seed per rack is required", dc.Spec.SeedsPerRack))
+	}
+
+	overlaps := dc.GetManagedSeedServiceOverlaps()
+	for _, seed := range dc.Spec.AdditionalSeeds {
+		if service, ok := overlaps[seed]; ok {
+			issues.reject("spec.additionalSeeds", attemptedTo("use additionalSeeds entry '%s', which is the operator-managed %s service of the datacenter", seed, service))
+		}
+	}
+
+	issues.reject("spec.rackDistributionStrategy", validateRackDistributionStrategy(dc))
+
+	if dc.Spec.RequireDistinctRackZones {
+		if warnings := getSharedRackZoneWarnings(dc); len(warnings) > 0 {
+			issues.reject("spec.racks", attemptedTo("pin several racks to one zone with requireDistinctRackZones set, %s", strings.Join(warnings, "; ")))
+		}
+	}
+
+	issues.reject("spec.logLevel", validateLogLevel(dc))
+
+	if key := dc.Spec.AntiAffinityTopologyKey; key != "" {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			issues.reject("spec.antiAffinityTopologyKey", attemptedTo("use antiAffinityTopologyKey '%s' which is not a valid label key", key))
+		}
+	}
+
+	for _, key := range sortedKeys(dc.Spec.AdditionalPodAnnotations) {
+		if errs := validation.IsQualifiedName(key); len(errs) > 0 {
+			issues.reject("spec.additionalPodAnnotations", attemptedTo("use additionalPodAnnotations key '%s' which is not a valid annotation key", key))
+		}
+	}
+
+	if dc.EnforcesSuperuserConfig() {
+		if err := dc.ValidateSuperuserConfig(); err != nil {
+			issues.reject(getSuperuserConfigFieldPath(dc), attemptedTo("use conflicting superuser configuration, %s", err.Error()))
+		}
+	}
+
+	issues.reject("spec.nodeReplacementStrategy", validateNodeReplacementStrategy(dc))
+
+	issues.reject("spec.managementApiMode", validateManagementApiMode(dc))
+
+	issues.reject("spec.antiAffinityScope", validateAntiAffinityScope(dc))
+
+	for _, podName := range dc.Spec.ReplaceNodes {
+		if !isValidPodName(podName) {
+			issues.reject("spec.replaceNodes", attemptedTo("replace node '%s' which is not a valid pod name", podName))
+		}
+	}
+
+	issues.reject("spec.serverImagePullPolicy", validatePullPolicy("serverImagePullPolicy", dc.Spec.ServerImagePullPolicy))
+	issues.reject("spec.configBuilderImagePullPolicy", validatePullPolicy("configBuilderImagePullPolicy", dc.Spec.ConfigBuilderImagePullPolicy))
+
+	if dc.Spec.MinNodesPerRack < 0 {
+		issues.reject("spec.minNodesPerRack", attemptedTo("use minNodesPerRack %d, the minimum cannot be negative", dc.Spec.MinNodesPerRack))
+	}
+
+	if dc.Spec.ReconcileIntervalSeconds < 0 {
+		issues.reject("spec.reconcileIntervalSeconds", attemptedTo("use reconcileIntervalSeconds %d, the interval must be positive", dc.Spec.ReconcileIntervalSeconds))
+	}
+
+	if _, err := dc.GetContainerPorts(); err != nil {
+		issues.reject("spec.config", attemptedTo("use conflicting ports, %s", err.Error()))
+	}
+
+	issues.reject("spec.networking", validateNetworking(dc))
+
+	issues.reject("spec.systemAuthReplication", validateSystemAuthReplication(dc))
+
+	issues.reject("spec.storageConfig.dataDirectory", validateDataDirectory(dc))
+
+	issues.reject("spec.gcLogPath", validateGCLogPath(dc))
+
+	if configMountPath := dc.Spec.ConfigMountPath; configMountPath != "" {
+		if !path.IsAbs(configMountPath) || path.Clean(configMountPath) == "/" {
+			issues.reject("spec.configMountPath", attemptedTo("use config mount path '%s' which is not an absolute path below /", configMountPath))
+		} else if dc.GetConfigMountPath() == dc.GetDataDirectory() {
+			issues.reject("spec.configMountPath", attemptedTo("use config mount path '%s' which is also the data directory", configMountPath))
+		}
+	}
+
+	issues.reject("spec.resources", validateResourceLimits("resources", dc.Spec.Resources))
+	for i, rack := range dc.Spec.Racks {
+		if rack.Resources != nil {
+			issues.reject(fmt.Sprintf("spec.racks[%d].resources", i), validateResourceLimits(fmt.Sprintf("rack %s resources", rack.Name), *rack.Resources))
+		}
 	}
 
 	// if using multiple nodes per worker, requests and limits should be set for both cpu and memory
 	if dc.Spec.AllowMultipleNodesPerWorker {
-		if dc.Spec.Resources.Requests.Cpu().IsZero() ||
-			dc.Spec.Resources.Limits.Cpu().IsZero() ||
-			dc.Spec.Resources.Requests.Memory().IsZero() ||
-			dc.Spec.Resources.Limits.Memory().IsZero() {
+		for _, rack := range dc.GetRacks() {
+			resources := dc.GetRackResources(rack.Name)
+			if resources.Requests.Cpu().IsZero() ||
+				resources.Limits.Cpu().IsZero() ||
+				resources.Requests.Memory().IsZero() ||
+				resources.Limits.Memory().IsZero() {
+
+				issues.reject("spec.resources", attemptedTo("use multiple nodes per worker without cpu and memory requests and limits"))
+				break
+			}
+		}
+	}
+
+	return issues
+}
+
+// validateResourceLimits checks that the cpu and memory limits of the resources are not
+// below their requests, which the apiserver only rejects once the statefulset is written
+func validateResourceLimits(field string, resources corev1.ResourceRequirements) error {
+	for _, name := range []corev1.ResourceName{corev1.ResourceCPU, corev1.ResourceMemory} {
+		request, hasRequest := resources.Requests[name]
+		limit, hasLimit := resources.Limits[name]
+		if hasRequest && hasLimit && limit.Cmp(request) < 0 {
+			return attemptedTo("use %s with a %s limit of %s below its request of %s", field, name, limit.String(), request.String())
+		}
+	}
+	return nil
+}
+
+// sortedKeys returns the keys of the map in order
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// containsKey returns whether the key is set in any object nested in the given JSON value
+func containsKey(value interface{}, key string) bool {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for k, nested := range v {
+			if k == key || containsKey(nested, key) {
+				return true
+			}
+		}
+	case []interface{}:
+		for _, nested := range v {
+			if containsKey(nested, key) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// validateNetworking checks that the address related options are compatible. With
+// hostNetwork the pods listen on the worker address, and with nodePort they broadcast the
+// worker address, so neither works when several pods share a worker, nor do they combine.
+// A listen interface excludes a listen address set in the config.
+func validateNetworking(dc CassandraDatacenter) error {
+	hostNetwork := dc.IsHostNetworkEnabled()
+	nodePort := dc.IsNodePortEnabled()
+
+	if hostNetwork && nodePort {
+		return attemptedTo("use both hostNetwork and nodePort, pods on the host network already use the worker address")
+	}
+
+	// Cassandra refuses to start with both listen_address and listen_interface
+	if dc.Spec.Networking != nil && dc.Spec.Networking.ListenInterface != "" && dc.Spec.Config != nil {
+		var config map[string]interface{}
+		if err := json.Unmarshal(dc.Spec.Config, &config); err == nil {
+			if yaml, ok := config["cassandra-yaml"].(map[string]interface{}); ok {
+				if _, ok := yaml["listen_address"]; ok {
+					return attemptedTo("use listenInterface with listen_address set in config, the server listens on either an address or an interface")
+				}
+			}
+		}
+	}
+
+	if dc.Spec.RelaxedScheduling {
+		if hostNetwork {
+			return attemptedTo("use hostNetwork with relaxedScheduling, pods on the same worker would bind the same address and ports")
+		}
+		if nodePort {
+			return attemptedTo("use nodePort with relaxedScheduling, pods on the same worker would broadcast the same address")
+		}
+	}
+
+	if dc.Spec.AllowMultipleNodesPerWorker {
+		if hostNetwork {
+			return attemptedTo("use hostNetwork with allowMultipleNodesPerWorker, pods on the same worker would bind the same address and ports")
+		}
+		if nodePort {
+			return attemptedTo("use nodePort with allowMultipleNodesPerWorker, pods on the same worker would broadcast the same address")
+		}
+	}
+
+	return nil
+}
+
+// validateStoppedFlags checks that no flag acting on running pods is set on a stopped
+// datacenter
+func validateStoppedFlags(dc CassandraDatacenter) error {
+	if !dc.Spec.Stopped {
+		return nil
+	}
+
+	flags := []string{}
+	if dc.Spec.RollingRestartRequested {
+		flags = append(flags, "rollingRestartRequested")
+	}
+	if dc.Spec.CanaryUpgrade {
+		flags = append(flags, "canaryUpgrade")
+	}
+	if len(flags) > 0 {
+		return attemptedTo("set %s on a stopped datacenter, which has no pods to act on", strings.Join(flags, " and "))
+	}
+	return nil
+}
+
+// validateLogLevel checks that the log level is one of LogLevels
+func validateLogLevel(dc CassandraDatacenter) error {
+	if dc.Spec.LogLevel == "" {
+		return nil
+	}
+	for _, level := range LogLevels {
+		if dc.Spec.LogLevel == level {
+			return nil
+		}
+	}
+	return attemptedTo("use log level '%s', expected one of %s", dc.Spec.LogLevel, strings.Join(LogLevels, ", "))
+}
+
+// getSharedRackZoneWarnings describes the racks pinned to the same zone, ordered by zone
+func getSharedRackZoneWarnings(dc CassandraDatacenter) []string {
+	racksByZone := dc.GetRacksSharingZones()
+	zones := make([]string, 0, len(racksByZone))
+	for zone := range racksByZone {
+		zones = append(zones, zone)
+	}
+	sort.Strings(zones)
+
+	warnings := []string{}
+	for _, zone := range zones {
+		warnings = append(warnings, fmt.Sprintf("racks %s share zone %s", strings.Join(racksByZone[zone], ", "), zone))
+	}
+	return warnings
+}
+
+func validateNodeReplacementStrategy(dc CassandraDatacenter) error {
+	strategy := dc.Spec.NodeReplacementStrategy
+	if strategy == "" {
+		return nil
+	}
+	for _, known := range NodeReplacementStrategies {
+		if strategy == known {
+			return nil
+		}
+	}
+	return attemptedTo("use nodeReplacementStrategy '%s', expected one of %s", strategy, strings.Join(NodeReplacementStrategies, ", "))
+}
+
+func validateManagementApiMode(dc CassandraDatacenter) error {
+	mode := dc.Spec.ManagementApiMode
+	if mode == "" {
+		return nil
+	}
+	for _, known := range ManagementApiModes {
+		if mode == known {
+			if mode == ManagementApiModeSidecar && dc.Spec.ManagementApiImage == "" {
+				return attemptedTo("use managementApiMode %s without managementApiImage", mode)
+			}
+			return nil
+		}
+	}
+	return attemptedTo("use managementApiMode '%s', expected one of %s", mode, strings.Join(ManagementApiModes, ", "))
+}
+
+func validateAntiAffinityScope(dc CassandraDatacenter) error {
+	scope := dc.Spec.AntiAffinityScope
+	if scope == "" {
+		return nil
+	}
+	for _, known := range AntiAffinityScopes {
+		if scope == known {
+			return nil
+		}
+	}
+	return attemptedTo("use antiAffinityScope '%s', expected one of %s", scope, strings.Join(AntiAffinityScopes, ", "))
+}
+
+func validateRackDistributionStrategy(dc CassandraDatacenter) error {
+	strategy := dc.Spec.RackDistributionStrategy
+	if strategy == "" {
+		return nil
+	}
+	for _, known := range RackDistributionStrategies {
+		if strategy == known {
+			return nil
+		}
+	}
+	return attemptedTo("use rackDistributionStrategy '%s', expected one of %s", strategy, strings.Join(RackDistributionStrategies, ", "))
+}
+
+// validatePullPolicy checks that the image pull policy of the field is a valid PullPolicy
+func validatePullPolicy(field string, policy corev1.PullPolicy) error {
+	switch policy {
+	case "", corev1.PullAlways, corev1.PullNever, corev1.PullIfNotPresent:
+		return nil
+	default:
+		return attemptedTo("use %s '%s', expected one of %s, %s, %s", field, policy,
+			corev1.PullAlways, corev1.PullNever, corev1.PullIfNotPresent)
+	}
+}
 
-			return attemptedTo("use multiple nodes per worker without cpu and memory requests and limits")
+// validateSystemAuthReplication checks that the system_auth replication includes this
+// datacenter, with no more replicas than it has nodes
+func validateSystemAuthReplication(dc CassandraDatacenter) error {
+	if len(dc.Spec.SystemAuthReplication) == 0 {
+		return nil
+	}
+
+	for dcName, rf := range dc.Spec.SystemAuthReplication {
+		if rf < 1 {
+			return attemptedTo("use system_auth replication factor %d for datacenter %s", rf, dcName)
 		}
 	}
 
+	rf, ok := dc.Spec.SystemAuthReplication[dc.Name]
+	if !ok {
+		return attemptedTo("set systemAuthReplication without datacenter %s", dc.Name)
+	}
+	if rf > dc.Spec.Size {
+		return attemptedTo("use system_auth replication factor %d with only %d nodes", rf, dc.Spec.Size)
+	}
+
 	return nil
 }
 
+// validateDataDirectory checks that the data directory is an absolute path and that it
+// does not collide with the mount path of an additional volume. Additional volumes may
+// still be mounted below it, e.g. to put the commitlog on a separate volume.
+func validateDataDirectory(dc CassandraDatacenter) error {
+	dataDir := dc.Spec.StorageConfig.DataDirectory
+	if dataDir == "" {
+		return nil
+	}
+
+	if !path.IsAbs(dataDir) || path.Clean(dataDir) == "/" {
+		return attemptedTo("use data directory '%s' which is not an absolute path below /", dataDir)
+	}
+
+	dataDir = path.Clean(dataDir)
+	for _, volume := range dc.Spec.StorageConfig.AdditionalVolumes {
+		mountPath := path.Clean(volume.MountPath)
+		if dataDir == mountPath || strings.HasPrefix(dataDir, mountPath+"/") {
+			return attemptedTo("use data directory '%s' which conflicts with the mount path of additional volume '%s'", dataDir, volume.Name)
+		}
+	}
+
+	return nil
+}
+
+// validateGCLogPath checks that the GC log path is an absolute path below one of the
+// writable volumes of the cassandra container: the server logs volume, the server data
+// volume or an additional volume
+func validateGCLogPath(dc CassandraDatacenter) error {
+	logPath := dc.Spec.GCLogPath
+	if logPath == "" {
+		return nil
+	}
+
+	if !path.IsAbs(logPath) {
+		return attemptedTo("use GC log path '%s' which is not an absolute path", logPath)
+	}
+
+	logPath = path.Clean(logPath)
+	volumePaths := []string{DefaultServerLogDirectory, dc.GetDataDirectory()}
+	for _, volume := range dc.Spec.StorageConfig.AdditionalVolumes {
+		volumePaths = append(volumePaths, path.Clean(volume.MountPath))
+	}
+	for _, volumePath := range volumePaths {
+		if strings.HasPrefix(logPath, volumePath+"/") {
+			return nil
+		}
+	}
+
+	return attemptedTo("use GC log path '%s' which is not below a writable volume, expected a path below one of %s", logPath, strings.Join(volumePaths, ", "))
+}
+
+// specImage is an image set in a field of the spec
+type specImage struct {
+	field string
+	image string
+}
+
+func (i specImage) String() string {
+	return fmt.Sprintf("%s '%s'", i.field, i.image)
+}
+
+// findUntaggedImages returns the images set in the spec which have no explicit tag or
+// digest, and so pull "latest". Unset images are skipped, as the operator then uses
+// pinned defaults.
+func findUntaggedImages(dc CassandraDatacenter) []specImage {
+	untagged := []specImage{}
+	if dc.Spec.ServerImage != "" && !images.HasImageTagOrDigest(dc.Spec.ServerImage) {
+		untagged = append(untagged, specImage{"serverImage", dc.Spec.ServerImage})
+	}
+	if dc.Spec.ConfigBuilderImage != "" && !images.HasImageTagOrDigest(dc.Spec.ConfigBuilderImage) {
+		untagged = append(untagged, specImage{"configBuilderImage", dc.Spec.ConfigBuilderImage})
+	}
+	if dc.Spec.ManagementApiImage != "" && !images.HasImageTagOrDigest(dc.Spec.ManagementApiImage) {
+		untagged = append(untagged, specImage{"managementApiImage", dc.Spec.ManagementApiImage})
+	}
+	return untagged
+}
+
+// NodeLister lists the worker nodes of the k8s cluster
+// +kubebuilder:object:generate=false
+type NodeLister interface {
+	ListNodes() ([]corev1.Node, error)
+}
+
+// WebhookNodeLister is used by the validating webhook to warn about resource requests
+// that fit no worker node. The check is skipped while it is nil.
+var WebhookNodeLister NodeLister
+
+// WarnResourcesExceedAllocatable returns a warning for every rack where no node matching
+// the rack's node affinity labels has enough allocatable cpu and memory for the server
+// container's resource requests. These are warnings rather than errors as the k8s cluster
+// may still scale up. Nothing is checked when the lister is nil.
+func WarnResourcesExceedAllocatable(dc CassandraDatacenter, lister NodeLister) []string {
+	if lister == nil {
+		return nil
+	}
+
+	nodes, err := lister.ListNodes()
+	if err != nil {
+		log.Error(err, "failed to list nodes, skipping node allocatable check")
+		return nil
+	}
+
+	warnings := []string{}
+	for _, rack := range dc.GetRacks() {
+		requests := dc.GetRackResources(rack.Name).Requests
+		if requests.Cpu().IsZero() && requests.Memory().IsZero() {
+			continue
+		}
+
+		affinityLabels := map[string]string{}
+		for k, v := range dc.Spec.NodeAffinityLabels {
+			affinityLabels[k] = v
+		}
+		for k, v := range rack.NodeAffinityLabels {
+			affinityLabels[k] = v
+		}
+		if rack.Zone != "" {
+			affinityLabels[corev1.LabelZoneFailureDomain] = rack.Zone
+		}
+
+		fits := false
+		for _, node := range nodes {
+			if !nodeMatchesLabels(node, affinityLabels) {
+				continue
+			}
+			allocatable := node.Status.Allocatable
+			if requests.Cpu().Cmp(*allocatable.Cpu()) <= 0 && requests.Memory().Cmp(*allocatable.Memory()) <= 0 {
+				fits = true
+				break
+			}
+		}
+
+		if !fits {
+			where := fmt.Sprintf("rack %s", rack.Name)
+			if zone, ok := affinityLabels[corev1.LabelZoneFailureDomain]; ok {
+				where = fmt.Sprintf("%s in zone %s", where, zone)
+			}
+			warnings = append(warnings, fmt.Sprintf(
+				"no node for %s has enough allocatable resources for requests of cpu %s and memory %s",
+				where, requests.Cpu(), requests.Memory()))
+		}
+	}
+
+	return warnings
+}
+
+// EtcdObjectSizeLimitBytes is the default size limit of etcd requests, which bounds the
+// size of a stored datacenter
+const EtcdObjectSizeLimitBytes = 1536 * 1024
+
+// ObjectSizeWarningBytes is the serialized size of a datacenter above which the validating
+// webhook warns that it approaches EtcdObjectSizeLimitBytes. The check is skipped while it
+// is not positive.
+var ObjectSizeWarningBytes = 1024 * 1024
+
+// WarnObjectSize returns a warning when the datacenter serializes to more than threshold
+// bytes, as it then approaches the object size limit of etcd, which is mostly taken up by
+// Config. Nothing is checked when the threshold is not positive.
+func WarnObjectSize(dc CassandraDatacenter, threshold int) []string {
+	if threshold <= 0 {
+		return nil
+	}
+
+	serialized, err := json.Marshal(dc)
+	if err != nil {
+		log.Error(err, "failed to serialize datacenter, skipping object size check")
+		return nil
+	}
+	if len(serialized) <= threshold {
+		return nil
+	}
+
+	return []string{fmt.Sprintf(
+		"the datacenter is about %d KiB serialized, %d KiB of which is config, approaching the etcd object size limit of %d KiB, consider moving the config to a configSecret",
+		len(serialized)/1024, len(dc.Spec.Config)/1024, EtcdObjectSizeLimitBytes/1024)}
+}
+
+func isValidPodName(name string) bool {
+	return len(validation.IsDNS1123Subdomain(name)) == 0
+}
+
+// PodLister lists the pods of a namespace
+// +kubebuilder:object:generate=false
+type PodLister interface {
+	ListPods(namespace string) ([]corev1.Pod, error)
+}
+
+// WebhookPodLister is used by the validating webhook to warn about ReplaceNodes entries
+// that match no pod of the datacenter. The check is skipped while it is nil.
+var WebhookPodLister PodLister
+
+// WarnUnknownReplaceNodes returns a warning for every ReplaceNodes entry which is not the
+// name of a pod of the datacenter, as the operator would wait for it forever. Entries that
+// are not valid pod names are rejected by the webhook instead. Nothing is checked when the
+// lister is nil.
+func WarnUnknownReplaceNodes(dc CassandraDatacenter, lister PodLister) []string {
+	if lister == nil || len(dc.Spec.ReplaceNodes) == 0 {
+		return nil
+	}
+
+	pods, err := lister.ListPods(dc.Namespace)
+	if err != nil {
+		log.Error(err, "failed to list pods, skipping replace nodes check")
+		return nil
+	}
+
+	dcPods := map[string]bool{}
+	for _, pod := range pods {
+		if pod.Labels[ClusterLabel] == dc.Spec.ClusterName && pod.Labels[DatacenterLabel] == dc.Name {
+			dcPods[pod.Name] = true
+		}
+	}
+
+	warnings := []string{}
+	for _, podName := range dc.Spec.ReplaceNodes {
+		if isValidPodName(podName) && !dcPods[podName] {
+			warnings = append(warnings, fmt.Sprintf(
+				"replaceNodes entry %s is not a pod of the datacenter and will never be replaced", podName))
+		}
+	}
+
+	return warnings
+}
+
+func nodeMatchesLabels(node corev1.Node, labels map[string]string) bool {
+	for k, v := range labels {
+		if node.Labels[k] != v {
+			return false
+		}
+	}
+	return true
+}
+
 // ValidateDatacenterFieldChanges checks that no values are improperly changing while updating
 // a CassandraDatacenter
 func ValidateDatacenterFieldChanges(oldDc CassandraDatacenter, newDc CassandraDatacenter) error {
@@ -94,6 +800,13 @@ func ValidateDatacenterFieldChanges(oldDc CassandraDatacenter, newDc CassandraDa
 		return attemptedTo("change clusterName")
 	}
 
+	// The data files of cassandra and dse are not compatible
+	if oldDc.Spec.ServerType != newDc.Spec.ServerType {
+		return attemptedTo("change serverType from '%s' to '%s'",
+			oldDc.Spec.ServerType,
+			newDc.Spec.ServerType)
+	}
+
 	if oldDc.Spec.AllowMultipleNodesPerWorker != newDc.Spec.AllowMultipleNodesPerWorker {
 		return attemptedTo("change allowMultipleNodesPerWorker")
 	}
@@ -106,6 +819,12 @@ func ValidateDatacenterFieldChanges(oldDc CassandraDatacenter, newDc CassandraDa
 		return attemptedTo("change serviceAccount")
 	}
 
+	// The suffix is part of the datacenter name the nodes report, which keyspace
+	// replication refers to
+	if oldDc.Spec.DcSuffix != newDc.Spec.DcSuffix {
+		return attemptedTo("change dcSuffix")
+	}
+
 	// StorageConfig changes are disallowed
 	if !reflect.DeepEqual(oldDc.Spec.StorageConfig, newDc.Spec.StorageConfig) {
 		return attemptedTo("change storageConfig")
@@ -121,7 +840,7 @@ func ValidateDatacenterFieldChanges(oldDc CassandraDatacenter, newDc CassandraDa
 	newRacks := newDc.GetRacks()
 
 	if len(oldRacks) > len(newRacks) {
-		return attemptedTo("remove rack")
+		return attemptedTo("remove racks %s", strings.Join(newDc.GetRemovedRacks(&oldDc), ", "))
 	}
 
 	newRackCount := len(newRacks) - len(oldRacks)
@@ -144,6 +863,24 @@ func ValidateDatacenterFieldChanges(oldDc CassandraDatacenter, newDc CassandraDa
 		}
 	}
 
+	if err := validateMinNodesPerRack(oldDc, newDc); err != nil {
+		return err
+	}
+
+	// Changing rackDistributionStrategy or preferExtraNodes can move nodes between racks,
+	// which would need a decommission in one rack and a bootstrap in another. Every
+	// existing rack has to keep its nodes, grow on a scale up and shrink on a scale down.
+	oldCounts := oldDc.GetRackNodeCounts(int(oldDc.Spec.Size))
+	newCounts := newDc.GetRackNodeCounts(int(newDc.Spec.Size))
+	for index, oldCount := range oldCounts {
+		newCount := newCounts[index]
+		if (newCount < oldCount && newDc.Spec.Size >= oldDc.Spec.Size) ||
+			(newCount > oldCount && newDc.Spec.Size <= oldDc.Spec.Size) {
+			return attemptedTo("move nodes between racks by changing their distribution from %v to %v nodes per rack",
+				oldCounts, newCounts)
+		}
+	}
+
 	for index, oldRack := range oldRacks {
 		newRack := newRacks[index]
 		if oldRack.Name != newRack.Name {
@@ -161,17 +898,35 @@ func ValidateDatacenterFieldChanges(oldDc CassandraDatacenter, newDc CassandraDa
 	return nil
 }
 
+// validateMinNodesPerRack checks that a scale down leaves every rack with at least
+// minNodesPerRack nodes, using the node distribution of GetExpectedStatefulSets
+func validateMinNodesPerRack(oldDc CassandraDatacenter, newDc CassandraDatacenter) error {
+	minNodes := int(newDc.Spec.MinNodesPerRack)
+	if minNodes <= 0 || newDc.Spec.Size >= oldDc.Spec.Size {
+		return nil
+	}
+
+	oldNodeCounts := map[string]int{}
+	for _, sts := range oldDc.GetExpectedStatefulSets() {
+		oldNodeCounts[sts.RackName] = sts.NodeCount
+	}
+
+	for _, sts := range newDc.GetExpectedStatefulSets() {
+		if sts.NodeCount < minNodes && sts.NodeCount < oldNodeCounts[sts.RackName] {
+			return attemptedTo("scale down rack %s to %d nodes, below minNodesPerRack %d",
+				sts.RackName, sts.NodeCount, minNodes)
+		}
+	}
+
+	return nil
+}
+
 // +kubebuilder:webhook:path=/validate-cassandradatacenter,mutating=false,failurePolicy=ignore,groups=cassandra.datastax.com,resources=cassandradatacenters,verbs=create;update,versions=v1beta1,name=validate-cassandradatacenter-webhook
 var _ webhook.Validator = &CassandraDatacenter{}
 
 func (dc *CassandraDatacenter) ValidateCreate() error {
 	log.Info("Validating webhook called for create")
-	err := ValidateSingleDatacenter(*dc)
-	if err != nil {
-		return err
-	}
-
-	return nil
+	return validateWrite(*dc)
 }
 
 func (dc *CassandraDatacenter) ValidateUpdate(old runtime.Object) error {
@@ -181,14 +936,88 @@ func (dc *CassandraDatacenter) ValidateUpdate(old runtime.Object) error {
 		return errors.New("old object in ValidateUpdate cannot be cast to CassandraDatacenter")
 	}
 
-	err := ValidateSingleDatacenter(*dc)
+	err := validateWrite(*dc)
 	if err != nil {
 		return err
 	}
 
+	if err := validateSuperuserConfigChange(*oldDc, *dc); err != nil {
+		return err
+	}
+
 	return ValidateDatacenterFieldChanges(*oldDc, *dc)
 }
 
+// validateSuperuserConfigChange rejects an ambiguous superuser configuration on an update
+// changing superuserSecretName or users, also of datacenters which no longer
+// EnforcesSuperuserConfig. Otherwise these keep their configuration with a warning.
+func validateSuperuserConfigChange(oldDc CassandraDatacenter, newDc CassandraDatacenter) error {
+	if oldDc.Spec.SuperuserSecretName == newDc.Spec.SuperuserSecretName &&
+		reflect.DeepEqual(oldDc.Spec.Users, newDc.Spec.Users) {
+		return nil
+	}
+
+	if err := newDc.ValidateSuperuserConfig(); err != nil {
+		return attemptedTo("use conflicting superuser configuration, %s", err.Error())
+	}
+	return nil
+}
+
+// validateWrite rejects the datacenter with the first error of ValidateStructured, and
+// logs its warnings otherwise
+func validateWrite(dc CassandraDatacenter) error {
+	issues := dc.ValidateStructured()
+	if err := firstValidationError(issues); err != nil {
+		return err
+	}
+
+	for _, issue := range issues {
+		log.Info("CassandraDatacenter validation warning", "datacenter", dc.Name,
+			"field", issue.FieldPath, "warning", issue.Message)
+	}
+	return nil
+}
+
+// getValidationWarnings returns the problems of the datacenter which the validating
+// webhook logs rather than rejecting the datacenter for
+func getValidationWarnings(dc CassandraDatacenter) []ValidationIssue {
+	issues := validationIssues{}
+	issues.warn("spec.resources", WarnResourcesExceedAllocatable(dc, WebhookNodeLister)...)
+	issues.warn("spec.replaceNodes", WarnUnknownReplaceNodes(dc, WebhookPodLister)...)
+	issues.warn("spec.config", WarnObjectSize(dc, ObjectSizeWarningBytes)...)
+	if !dc.Spec.RequireDistinctRackZones {
+		issues.warn("spec.racks", getSharedRackZoneWarnings(dc)...)
+	}
+	for _, image := range findUntaggedImages(dc) {
+		issues.warn("spec."+image.field, fmt.Sprintf("%s has no tag or digest and pulls latest", image))
+	}
+	if !dc.EnforcesSuperuserConfig() {
+		if err := dc.ValidateSuperuserConfig(); err != nil {
+			issues.warn(getSuperuserConfigFieldPath(dc), err.Error())
+		}
+	}
+	return issues
+}
+
+// getSuperuserConfigFieldPath returns the field path of the issue ValidateSuperuserConfig
+// reports for the datacenter
+func getSuperuserConfigFieldPath(dc CassandraDatacenter) string {
+	if errs := validation.IsDNS1123Subdomain(dc.Spec.SuperuserSecretName); dc.Spec.SuperuserSecretName != "" && len(errs) > 0 {
+		return "spec.superuserSecretName"
+	}
+	return "spec.users"
+}
+
+// GetValidationWarnings returns the problems of the datacenter which the validating webhook
+// logs rather than rejecting the datacenter for
+func GetValidationWarnings(dc CassandraDatacenter) []string {
+	warnings := []string{}
+	for _, issue := range getValidationWarnings(dc) {
+		warnings = append(warnings, issue.Message)
+	}
+	return warnings
+}
+
 func (dc *CassandraDatacenter) ValidateDelete() error {
 	return nil
 }
@@ -8,6 +8,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"k8s.io/apimachinery/pkg/api/resource"
 
 	corev1 "k8s.io/api/core/v1"
@@ -15,6 +16,7 @@ import (
 )
 
 func Test_ValidateSingleDatacenter(t *testing.T) {
+	negativeSize := int64(-1)
 	tests := []struct {
 		name      string
 		dc        *CassandraDatacenter
@@ -233,6 +235,911 @@ func Test_ValidateSingleDatacenter(t *testing.T) {
 			},
 			errString: "use multiple nodes per worker without cpu and memory requests and limits",
 		},
+		{
+			name: "Data directory with nested additional volume Valid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					StorageConfig: StorageConfig{
+						DataDirectory: "/data/cassandra",
+						AdditionalVolumes: AdditionalVolumesSlice{
+							{Name: "commitlog", MountPath: "/data/cassandra/commitlog"},
+						},
+					},
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "Relative data directory Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					StorageConfig: StorageConfig{
+						DataDirectory: "data/cassandra",
+					},
+				},
+			},
+			errString: "use data directory 'data/cassandra' which is not an absolute path below /",
+		},
+		{
+			name: "Data directory inside additional volume Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					StorageConfig: StorageConfig{
+						DataDirectory: "/data/cassandra/",
+						AdditionalVolumes: AdditionalVolumesSlice{
+							{Name: "data", MountPath: "/data"},
+						},
+					},
+				},
+			},
+			errString: "use data directory '/data/cassandra' which conflicts with the mount path of additional volume 'data'",
+		},
+		{
+			name: "HostNetwork and NodePort Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Networking: &NetworkingConfig{
+						HostNetwork: true,
+						NodePort:    &NodePortConfig{},
+					},
+				},
+			},
+			errString: "use both hostNetwork and nodePort, pods on the host network already use the worker address",
+		},
+		{
+			name: "HostNetwork with multiple nodes per worker Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Networking: &NetworkingConfig{
+						HostNetwork: true,
+					},
+					AllowMultipleNodesPerWorker: true,
+				},
+			},
+			errString: "use hostNetwork with allowMultipleNodesPerWorker, pods on the same worker would bind the same address and ports",
+		},
+		{
+			name: "NodePort with multiple nodes per worker Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Networking: &NetworkingConfig{
+						NodePort: &NodePortConfig{},
+					},
+					AllowMultipleNodesPerWorker: true,
+				},
+			},
+			errString: "use nodePort with allowMultipleNodesPerWorker, pods on the same worker would broadcast the same address",
+		},
+		{
+			name: "System auth replication Valid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:            "cassandra",
+					ServerVersion:         "3.11.7",
+					Size:                  3,
+					SystemAuthReplication: map[string]int32{"exampleDC": 3, "otherDC": 5},
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "System auth replication without this datacenter Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:            "cassandra",
+					ServerVersion:         "3.11.7",
+					Size:                  3,
+					SystemAuthReplication: map[string]int32{"otherDC": 3},
+				},
+			},
+			errString: "set systemAuthReplication without datacenter exampleDC",
+		},
+		{
+			name: "System auth replication exceeding size Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:            "cassandra",
+					ServerVersion:         "3.11.7",
+					Size:                  2,
+					SystemAuthReplication: map[string]int32{"exampleDC": 3},
+				},
+			},
+			errString: "use system_auth replication factor 3 with only 2 nodes",
+		},
+		{
+			name: "Seed provider seeds in config Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Config:        json.RawMessage(`{"cassandra-yaml":{"seed_provider":[{"class_name":"org.apache.cassandra.locator.SimpleSeedProvider","parameters":[{"seeds":"10.0.0.1"}]}]}}`),
+				},
+			},
+			errString: "set seeds through cassandra-yaml seed_provider in config, use additionalSeeds instead",
+		},
+		{
+			name: "Server image without tag Valid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					ServerImage:   "localhost:5000/datastax/cassandra-mgmtapi",
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "Malformed server image Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					ServerImage:   "datastax/cassandra-mgmtapi::3.11.7",
+				},
+			},
+			errString: "use serverImage 'datastax/cassandra-mgmtapi::3.11.7' which is not a valid image reference, expected [registry/]repository[:tag][@digest]",
+		},
+		{
+			name: "Negative seeds per rack Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					SeedsPerRack:  -1,
+				},
+			},
+			errString: "use seedsPerRack -1, at least one seed per rack is required",
+		},
+		{
+			name: "Negative reconcile interval Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:               "cassandra",
+					ServerVersion:            "3.11.7",
+					ReconcileIntervalSeconds: -30,
+				},
+			},
+			errString: "use reconcileIntervalSeconds -30, the interval must be positive",
+		},
+		{
+			name: "Relaxed scheduling with hostNetwork Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:        "cassandra",
+					ServerVersion:     "3.11.7",
+					RelaxedScheduling: true,
+					Networking: &NetworkingConfig{
+						HostNetwork: true,
+					},
+				},
+			},
+			errString: "use hostNetwork with relaxedScheduling, pods on the same worker would bind the same address and ports",
+		},
+		{
+			name: "Prometheus port conflict Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "dse",
+					ServerVersion: "6.8.4",
+					Config:        json.RawMessage(`{"10-write-prom-conf":{"enabled":true,"port":8609}}`),
+				},
+			},
+			errString: "use conflicting ports, container ports prometheus and internode-msg both use port 8609",
+		},
+		{
+			name: "Storage port conflict Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Config:        json.RawMessage(`{"cassandra-yaml":{"ssl_storage_port":7199}}`),
+				},
+			},
+			errString: "use conflicting ports, container ports tls-internode and jmx both use port 7199",
+		},
+		{
+			name: "Unknown log level Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					LogLevel:      "debug",
+				},
+			},
+			errString: "use log level 'debug', expected one of ERROR, WARN, INFO, DEBUG, TRACE",
+		},
+		{
+			name: "Relative config mount path Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:      "cassandra",
+					ServerVersion:   "3.11.7",
+					ConfigMountPath: "etc/cassandra",
+				},
+			},
+			errString: "use config mount path 'etc/cassandra' which is not an absolute path below /",
+		},
+		{
+			name: "Untagged config builder image Valid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:         "cassandra",
+					ServerVersion:      "3.11.7",
+					ConfigBuilderImage: "datastax/cass-config-builder",
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "Untagged config builder image with requireImageTags Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:         "cassandra",
+					ServerVersion:      "3.11.7",
+					ConfigBuilderImage: "datastax/cass-config-builder",
+					RequireImageTags:   true,
+				},
+			},
+			errString: "use configBuilderImage 'datastax/cass-config-builder' without a tag or digest with requireImageTags",
+		},
+		{
+			name: "Config structure with strictConfigValidation Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:             "cassandra",
+					ServerVersion:          "4.0.0",
+					StrictConfigValidation: true,
+					Config:                 json.RawMessage(`{"cassandra-yaml":{"num_tokens":8},"cassandra-yaml.concurrent_reads":32}`),
+				},
+			},
+			errString: "use config the config builder would reject, config key cassandra-yaml.concurrent_reads uses dot notation, it has to be nested in cassandra-yaml",
+		},
+		{
+			name: "Config structure without strictConfigValidation Valid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "4.0.0",
+					Config:        json.RawMessage(`{"cassandra-yaml":{"num_tokens":8},"cassandra-yaml.concurrent_reads":32}`),
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "Server image pull policy Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:            "cassandra",
+					ServerVersion:         "3.11.7",
+					ServerImagePullPolicy: "Sometimes",
+				},
+			},
+			errString: "use serverImagePullPolicy 'Sometimes', expected one of Always, Never, IfNotPresent",
+		},
+		{
+			name: "Anti-affinity topology key Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:              "cassandra",
+					ServerVersion:           "3.11.7",
+					AntiAffinityTopologyKey: "topology.kubernetes.io/zone/",
+				},
+			},
+			errString: "use antiAffinityTopologyKey 'topology.kubernetes.io/zone/' which is not a valid label key",
+		},
+		{
+			name: "Anti-affinity topology key Valid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:              "cassandra",
+					ServerVersion:           "3.11.7",
+					AntiAffinityTopologyKey: "topology.kubernetes.io/zone",
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "Stopped with rolling restart and canary upgrade Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:              "cassandra",
+					ServerVersion:           "3.11.7",
+					Stopped:                 true,
+					RollingRestartRequested: true,
+					CanaryUpgrade:           true,
+				},
+			},
+			errString: "set rollingRestartRequested and canaryUpgrade on a stopped datacenter, which has no pods to act on",
+		},
+		{
+			name: "Stopped with canary upgrade Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Stopped:       true,
+					CanaryUpgrade: true,
+				},
+			},
+			errString: "set canaryUpgrade on a stopped datacenter, which has no pods to act on",
+		},
+		{
+			name: "Durability max hint window Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Durability: &DurabilityConfig{
+						MaxHintWindow: "3 hours",
+					},
+				},
+			},
+			errString: "use invalid durability settings, maxHintWindow '3 hours' is not a duration",
+		},
+		{
+			name: "Durability throttle Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Durability: &DurabilityConfig{
+						BatchlogReplayThrottle: "1MB/s",
+					},
+				},
+			},
+			errString: "use invalid durability settings, batchlogReplayThrottle '1MB/s' is not a quantity",
+		},
+		{
+			name: "Durability group commitlog sync with Cassandra 3.11 Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Durability: &DurabilityConfig{
+						CommitlogSync:            "group",
+						CommitlogSyncGroupWindow: "15ms",
+					},
+				},
+			},
+			errString: "use durability with cassandra 3.11.7, which does not support cassandra-yaml.commitlog_sync_group_window_in_ms",
+		},
+		{
+			name: "Request timeout Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					RequestTimeouts: &RequestTimeoutsConfig{
+						ReadMs: -1,
+					},
+				},
+			},
+			errString: "use invalid request timeouts, readMs -1 is not a positive number of milliseconds",
+		},
+		{
+			name: "Rack distribution strategy Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:               "cassandra",
+					ServerVersion:            "3.11.7",
+					RackDistributionStrategy: "Random",
+				},
+			},
+			errString: "use rackDistributionStrategy 'Random', expected one of FirstRacks, LastRacks, PreferredRacks",
+		},
+		{
+			name: "Replace nodes with invalid pod name",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					ReplaceNodes:  []string{"cluster1-dc1-r1-sts-0 "},
+				},
+			},
+			errString: "replace node 'cluster1-dc1-r1-sts-0 ' which is not a valid pod name",
+		},
+		{
+			name: "Commitlog sync mode Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Durability: &DurabilityConfig{
+						CommitlogSync: "always",
+					},
+				},
+			},
+			errString: "use invalid durability settings, commitlogSync 'always' is neither periodic, batch nor group",
+		},
+		{
+			name: "Commitlog sync periodic without period Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Durability: &DurabilityConfig{
+						CommitlogSync: "periodic",
+					},
+				},
+			},
+			errString: "use invalid durability settings, commitlogSyncPeriod is required with the periodic commitlogSync",
+		},
+		{
+			name: "Commitlog sync period not positive Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Durability: &DurabilityConfig{
+						CommitlogSyncPeriod: "0s",
+					},
+				},
+			},
+			errString: "use invalid durability settings, commitlogSyncPeriod '0s' is not a positive duration",
+		},
+		{
+			name: "Commitlog sync period with batch Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Durability: &DurabilityConfig{
+						CommitlogSync:       "batch",
+						CommitlogSyncPeriod: "10s",
+					},
+				},
+			},
+			errString: "use invalid durability settings, commitlogSyncPeriod only applies to the periodic commitlogSync",
+		},
+		{
+			name: "Racks sharing a zone",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Racks:         []Rack{{Name: "r1", Zone: "zone-a"}, {Name: "r2", Zone: "zone-a"}},
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "Racks sharing a zone with requireDistinctRackZones Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:               "cassandra",
+					ServerVersion:            "3.11.7",
+					RequireDistinctRackZones: true,
+					Racks:                    []Rack{{Name: "r1", Zone: "zone-a"}, {Name: "r2", Zone: "zone-a"}, {Name: "r3", Zone: "zone-b"}},
+				},
+			},
+			errString: "pin several racks to one zone with requireDistinctRackZones set, racks r1, r2 share zone zone-a",
+		},
+		{
+			name: "Negative cache size Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					MemtablesAndCaches: &MemtablesAndCachesConfig{
+						KeyCacheSizeMb: &negativeSize,
+					},
+				},
+			},
+			errString: "use invalid memtable and cache sizes, keyCacheSizeMb -1 is negative",
+		},
+		{
+			name: "Node replacement strategy Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:              "cassandra",
+					ServerVersion:           "3.11.7",
+					NodeReplacementStrategy: "replace",
+				},
+			},
+			errString: "use nodeReplacementStrategy 'replace', expected one of Replace, Restart",
+		},
+		{
+			name: "Additional pod annotation key Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:               "cassandra",
+					ServerVersion:            "3.11.7",
+					AdditionalPodAnnotations: map[string]string{"backup tool": "enabled"},
+				},
+			},
+			errString: "use additionalPodAnnotations key 'backup tool' which is not a valid annotation key",
+		},
+		{
+			name: "Management API mode Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:        "cassandra",
+					ServerVersion:     "3.11.7",
+					ManagementApiMode: "sidecar",
+				},
+			},
+			errString: "use managementApiMode 'sidecar', expected one of InContainer, Sidecar",
+		},
+		{
+			name: "Management API sidecar without image",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:        "cassandra",
+					ServerVersion:     "3.11.7",
+					ManagementApiMode: "Sidecar",
+				},
+			},
+			errString: "use managementApiMode Sidecar without managementApiImage",
+		},
+		{
+			name: "Additional seeds overlapping the seed service Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "exampleDC",
+					Namespace: "example",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName:     "exampleCluster",
+					ServerType:      "cassandra",
+					ServerVersion:   "3.11.7",
+					AdditionalSeeds: []string{"10.0.0.1", "exampleCluster-seed-service.example.svc.cluster.local"},
+				},
+			},
+			errString: "use additionalSeeds entry 'exampleCluster-seed-service.example.svc.cluster.local', which is the operator-managed exampleCluster-seed-service service of the datacenter",
+		},
+		{
+			name: "GC log path below an additional volume",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:      "cassandra",
+					ServerVersion:   "3.11.7",
+					EnableGCLogging: true,
+					GCLogPath:       "/var/gclogs/gc.log",
+					StorageConfig: StorageConfig{
+						AdditionalVolumes: AdditionalVolumesSlice{
+							{Name: "gclogs", MountPath: "/var/gclogs"},
+						},
+					},
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "GC log path not absolute",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:      "cassandra",
+					ServerVersion:   "3.11.7",
+					EnableGCLogging: true,
+					GCLogPath:       "logs/gc.log",
+				},
+			},
+			errString: "use GC log path 'logs/gc.log' which is not an absolute path",
+		},
+		{
+			name: "GC log path not on a writable volume",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:      "cassandra",
+					ServerVersion:   "3.11.7",
+					EnableGCLogging: true,
+					GCLogPath:       "/tmp/gc.log",
+				},
+			},
+			errString: "use GC log path '/tmp/gc.log' which is not below a writable volume, expected a path below one of /var/log/cassandra, /var/lib/cassandra",
+		},
+		{
+			name: "Memory limit below request Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("1000m"),
+							corev1.ResourceMemory: resource.MustParse("8Gi"),
+						},
+						Limits: corev1.ResourceList{
+							corev1.ResourceCPU:    resource.MustParse("1000m"),
+							corev1.ResourceMemory: resource.MustParse("4Gi"),
+						},
+					},
+				},
+			},
+			errString: "use resources with a memory limit of 4Gi below its request of 8Gi",
+		},
+		{
+			name: "Rack cpu limit below request Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Racks: []Rack{{
+						Name: "rack1",
+						Resources: &corev1.ResourceRequirements{
+							Requests: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("2"),
+							},
+							Limits: corev1.ResourceList{
+								corev1.ResourceCPU: resource.MustParse("500m"),
+							},
+						},
+					}},
+				},
+			},
+			errString: "use rack rack1 resources with a cpu limit of 500m below its request of 2",
+		},
+		{
+			name: "Unknown anti-affinity scope Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:        "cassandra",
+					ServerVersion:     "3.11.7",
+					AntiAffinityScope: "Namespace",
+				},
+			},
+			errString: "use antiAffinityScope 'Namespace', expected one of Datacenter, Cluster",
+		},
+		{
+			name: "Listen interface with listen address Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:          "cassandra",
+					ServerVersion:       "3.11.7",
+					ForceConfigOverride: true,
+					Networking: &NetworkingConfig{
+						HostNetwork:     true,
+						ListenInterface: "bond0",
+					},
+					Config: json.RawMessage(`{"cassandra-yaml":{"listen_address":"10.0.0.1"}}`),
+				},
+			},
+			errString: "use listenInterface with listen_address set in config, the server listens on either an address or an interface",
+		},
+		{
+			name: "Listen interface name Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Networking: &NetworkingConfig{
+						ListenInterface: "eth0/1",
+					},
+				},
+			},
+			errString: "use an invalid listen interface, listenInterface 'eth0/1' is not a network interface name",
+		},
+		{
+			name: "Listen interface IPv6 preference without interface Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Networking: &NetworkingConfig{
+						ListenInterfacePreferIPv6: true,
+					},
+				},
+			},
+			errString: "use an invalid listen interface, listenInterfacePreferIPv6 requires listenInterface",
+		},
+		{
+			name: "Reserved config keys Invalid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+					Config:        json.RawMessage(`{"cassandra-yaml":{"cluster_name":"other","num_tokens":8},"cassandra-rackdc-properties":{"rack":"r1"}}`),
+				},
+			},
+			errString: "set reserved config keys cassandra-yaml.cluster_name, cassandra-rackdc-properties.rack without forceConfigOverride",
+		},
+		{
+			name: "Reserved config keys with disableConfigValidation Valid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:              "cassandra",
+					ServerVersion:           "3.11.7",
+					Config:                  json.RawMessage(`{"cassandra-yaml":{"cluster_name":"other"}}`),
+					DisableConfigValidation: true,
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "Reserved config keys with forceConfigOverride Valid",
+			dc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:          "cassandra",
+					ServerVersion:       "3.11.7",
+					Config:              json.RawMessage(`{"cassandra-yaml":{"cluster_name":"other"}}`),
+					ForceConfigOverride: true,
+				},
+			},
+			errString: "",
+		},
 	}
 
 	for _, tt := range tests {
@@ -262,16 +1169,220 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 		errString string
 	}{
 		{
-			name: "No significant changes",
+			name: "No significant changes",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName:                 "oldname",
+					AllowMultipleNodesPerWorker: false,
+					SuperuserSecretName:         "hush",
+					ServiceAccount:              "admin",
+					StorageConfig: StorageConfig{
+						CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+							StorageClassName: &storageName,
+							AccessModes:      []corev1.PersistentVolumeAccessMode{"ReadWriteOnce"},
+							Resources: corev1.ResourceRequirements{
+								Requests: map[corev1.ResourceName]resource.Quantity{"storage": storageSize},
+							},
+						},
+					},
+					Racks: []Rack{{
+						Name: "rack0",
+						Zone: "zone0",
+					}, {
+						Name: "rack1",
+						Zone: "zone1",
+					}, {
+						Name: "rack2",
+						Zone: "zone2",
+					}},
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName:                 "oldname",
+					AllowMultipleNodesPerWorker: false,
+					SuperuserSecretName:         "hush",
+					ServiceAccount:              "admin",
+					StorageConfig: StorageConfig{
+						CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+							StorageClassName: &storageName,
+							AccessModes:      []corev1.PersistentVolumeAccessMode{"ReadWriteOnce"},
+							Resources: corev1.ResourceRequirements{
+								Requests: map[corev1.ResourceName]resource.Quantity{"storage": storageSize},
+							},
+						},
+					},
+					Racks: []Rack{{
+						Name: "rack0",
+						Zone: "zone0",
+					}, {
+						Name: "rack1",
+						Zone: "zone1",
+					}, {
+						Name: "rack2",
+						Zone: "zone2",
+					}},
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "Clustername changed",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "oldname",
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ClusterName: "newname",
+				},
+			},
+			errString: "change clusterName",
+		},
+		{
+			name: "AllowMultipleNodesPerWorker changed",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					AllowMultipleNodesPerWorker: false,
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					AllowMultipleNodesPerWorker: true,
+				},
+			},
+			errString: "change allowMultipleNodesPerWorker",
+		},
+		{
+			name: "SuperuserSecretName changed",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					SuperuserSecretName: "hush",
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					SuperuserSecretName: "newsecret",
+				},
+			},
+			errString: "change superuserSecretName",
+		},
+		{
+			name: "ServerType changed",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "dse",
+					ServerVersion: "6.8.4",
+				},
+			},
+			errString: "change serverType from 'cassandra' to 'dse'",
+		},
+		{
+			name: "ServerVersion changed",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "3.11.7",
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServerType:    "cassandra",
+					ServerVersion: "4.0.0",
+				},
+			},
+			errString: "",
+		},
+		{
+			name: "ServiceAccount changed",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServiceAccount: "admin",
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					ServiceAccount: "newadmin",
+				},
+			},
+			errString: "change serviceAccount",
+		},
+		{
+			name: "DcSuffix changed",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					DcSuffix: "_east",
+				},
+			},
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					DcSuffix: "_west",
+				},
+			},
+			errString: "change dcSuffix",
+		},
+		{
+			name: "StorageConfig changes",
 			oldDc: &CassandraDatacenter{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "exampleDC",
 				},
 				Spec: CassandraDatacenterSpec{
-					ClusterName:                 "oldname",
-					AllowMultipleNodesPerWorker: false,
-					SuperuserSecretName:         "hush",
-					ServiceAccount:              "admin",
 					StorageConfig: StorageConfig{
 						CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
 							StorageClassName: &storageName,
@@ -281,16 +1392,6 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 							},
 						},
 					},
-					Racks: []Rack{{
-						Name: "rack0",
-						Zone: "zone0",
-					}, {
-						Name: "rack1",
-						Zone: "zone1",
-					}, {
-						Name: "rack2",
-						Zone: "zone2",
-					}},
 				},
 			},
 			newDc: &CassandraDatacenter{
@@ -298,19 +1399,26 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 					Name: "exampleDC",
 				},
 				Spec: CassandraDatacenterSpec{
-					ClusterName:                 "oldname",
-					AllowMultipleNodesPerWorker: false,
-					SuperuserSecretName:         "hush",
-					ServiceAccount:              "admin",
 					StorageConfig: StorageConfig{
 						CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
 							StorageClassName: &storageName,
-							AccessModes:      []corev1.PersistentVolumeAccessMode{"ReadWriteOnce"},
+							AccessModes:      []corev1.PersistentVolumeAccessMode{"ReadWriteMany"},
 							Resources: corev1.ResourceRequirements{
 								Requests: map[corev1.ResourceName]resource.Quantity{"storage": storageSize},
 							},
 						},
 					},
+				},
+			},
+			errString: "change storageConfig",
+		},
+		{
+			name: "Removing a rack",
+			oldDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
 					Racks: []Rack{{
 						Name: "rack0",
 						Zone: "zone0",
@@ -323,16 +1431,34 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 					}},
 				},
 			},
-			errString: "",
+			newDc: &CassandraDatacenter{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "exampleDC",
+				},
+				Spec: CassandraDatacenterSpec{
+					Racks: []Rack{{
+						Name: "rack0",
+						Zone: "zone0",
+					}, {
+						Name: "rack2",
+						Zone: "zone2",
+					}},
+				},
+			},
+			errString: "remove racks rack1",
 		},
 		{
-			name: "Clustername changed",
+			name: "Scaling down",
 			oldDc: &CassandraDatacenter{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "exampleDC",
 				},
 				Spec: CassandraDatacenterSpec{
-					ClusterName: "oldname",
+					Racks: []Rack{{
+						Name: "rack0",
+						Zone: "zone0",
+					}},
+					Size: 6,
 				},
 			},
 			newDc: &CassandraDatacenter{
@@ -340,19 +1466,29 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 					Name: "exampleDC",
 				},
 				Spec: CassandraDatacenterSpec{
-					ClusterName: "newname",
+					Racks: []Rack{{
+						Name: "rack0",
+						Zone: "zone0",
+					}},
+					Size: 3,
 				},
 			},
-			errString: "change clusterName",
+			errString: "",
 		},
 		{
-			name: "AllowMultipleNodesPerWorker changed",
+			name: "Scaling down to minNodesPerRack",
 			oldDc: &CassandraDatacenter{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "exampleDC",
 				},
 				Spec: CassandraDatacenterSpec{
-					AllowMultipleNodesPerWorker: false,
+					Racks: []Rack{{
+						Name: "rack0",
+					}, {
+						Name: "rack1",
+					}},
+					Size:            8,
+					MinNodesPerRack: 3,
 				},
 			},
 			newDc: &CassandraDatacenter{
@@ -360,19 +1496,31 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 					Name: "exampleDC",
 				},
 				Spec: CassandraDatacenterSpec{
-					AllowMultipleNodesPerWorker: true,
+					Racks: []Rack{{
+						Name: "rack0",
+					}, {
+						Name: "rack1",
+					}},
+					Size:            6,
+					MinNodesPerRack: 3,
 				},
 			},
-			errString: "change allowMultipleNodesPerWorker",
+			errString: "",
 		},
 		{
-			name: "SuperuserSecretName changed",
+			name: "Scaling down below minNodesPerRack",
 			oldDc: &CassandraDatacenter{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "exampleDC",
 				},
 				Spec: CassandraDatacenterSpec{
-					SuperuserSecretName: "hush",
+					Racks: []Rack{{
+						Name: "rack0",
+					}, {
+						Name: "rack1",
+					}},
+					Size:            8,
+					MinNodesPerRack: 3,
 				},
 			},
 			newDc: &CassandraDatacenter{
@@ -380,19 +1528,30 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 					Name: "exampleDC",
 				},
 				Spec: CassandraDatacenterSpec{
-					SuperuserSecretName: "newsecret",
+					Racks: []Rack{{
+						Name: "rack0",
+					}, {
+						Name: "rack1",
+					}},
+					Size:            5,
+					MinNodesPerRack: 3,
 				},
 			},
-			errString: "change superuserSecretName",
+			errString: "scale down rack rack1 to 2 nodes, below minNodesPerRack 3",
 		},
 		{
-			name: "ServiceAccount changed",
+			name: "Changed rack distribution strategy moving nodes",
 			oldDc: &CassandraDatacenter{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "exampleDC",
 				},
 				Spec: CassandraDatacenterSpec{
-					ServiceAccount: "admin",
+					Racks: []Rack{{
+						Name: "rack0",
+					}, {
+						Name: "rack1",
+					}},
+					Size: 3,
 				},
 			},
 			newDc: &CassandraDatacenter{
@@ -400,27 +1559,32 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 					Name: "exampleDC",
 				},
 				Spec: CassandraDatacenterSpec{
-					ServiceAccount: "newadmin",
+					Racks: []Rack{{
+						Name: "rack0",
+					}, {
+						Name: "rack1",
+					}},
+					Size:                     3,
+					RackDistributionStrategy: RackDistributionLastRacks,
 				},
 			},
-			errString: "change serviceAccount",
+			errString: "move nodes between racks by changing their distribution from [2 1] to [1 2] nodes per rack",
 		},
 		{
-			name: "StorageConfig changes",
+			name: "Changed rack distribution strategy while scaling up",
 			oldDc: &CassandraDatacenter{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "exampleDC",
 				},
 				Spec: CassandraDatacenterSpec{
-					StorageConfig: StorageConfig{
-						CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
-							StorageClassName: &storageName,
-							AccessModes:      []corev1.PersistentVolumeAccessMode{"ReadWriteOnce"},
-							Resources: corev1.ResourceRequirements{
-								Requests: map[corev1.ResourceName]resource.Quantity{"storage": storageSize},
-							},
-						},
-					},
+					Racks: []Rack{{
+						Name: "rack0",
+					}, {
+						Name: "rack1",
+					}, {
+						Name: "rack2",
+					}},
+					Size: 4,
 				},
 			},
 			newDc: &CassandraDatacenter{
@@ -428,21 +1592,21 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 					Name: "exampleDC",
 				},
 				Spec: CassandraDatacenterSpec{
-					StorageConfig: StorageConfig{
-						CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
-							StorageClassName: &storageName,
-							AccessModes:      []corev1.PersistentVolumeAccessMode{"ReadWriteMany"},
-							Resources: corev1.ResourceRequirements{
-								Requests: map[corev1.ResourceName]resource.Quantity{"storage": storageSize},
-							},
-						},
-					},
+					Racks: []Rack{{
+						Name: "rack0",
+					}, {
+						Name: "rack1",
+					}, {
+						Name: "rack2",
+					}},
+					Size:                     5,
+					RackDistributionStrategy: RackDistributionLastRacks,
 				},
 			},
-			errString: "change storageConfig",
+			errString: "move nodes between racks by changing their distribution from [2 1 1] to [1 2 2] nodes per rack",
 		},
 		{
-			name: "Removing a rack",
+			name: "Scaling up with the same rack distribution strategy",
 			oldDc: &CassandraDatacenter{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "exampleDC",
@@ -450,14 +1614,13 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 				Spec: CassandraDatacenterSpec{
 					Racks: []Rack{{
 						Name: "rack0",
-						Zone: "zone0",
 					}, {
 						Name: "rack1",
-						Zone: "zone1",
 					}, {
 						Name: "rack2",
-						Zone: "zone2",
 					}},
+					Size:                     4,
+					RackDistributionStrategy: RackDistributionLastRacks,
 				},
 			},
 			newDc: &CassandraDatacenter{
@@ -467,17 +1630,19 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 				Spec: CassandraDatacenterSpec{
 					Racks: []Rack{{
 						Name: "rack0",
-						Zone: "zone0",
+					}, {
+						Name: "rack1",
 					}, {
 						Name: "rack2",
-						Zone: "zone2",
 					}},
+					Size:                     5,
+					RackDistributionStrategy: RackDistributionLastRacks,
 				},
 			},
-			errString: "remove rack",
+			errString: "",
 		},
 		{
-			name: "Scaling down",
+			name: "Changed rack distribution strategy with balanced racks",
 			oldDc: &CassandraDatacenter{
 				ObjectMeta: metav1.ObjectMeta{
 					Name: "exampleDC",
@@ -485,9 +1650,10 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 				Spec: CassandraDatacenterSpec{
 					Racks: []Rack{{
 						Name: "rack0",
-						Zone: "zone0",
+					}, {
+						Name: "rack1",
 					}},
-					Size: 6,
+					Size: 4,
 				},
 			},
 			newDc: &CassandraDatacenter{
@@ -497,9 +1663,11 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 				Spec: CassandraDatacenterSpec{
 					Racks: []Rack{{
 						Name: "rack0",
-						Zone: "zone0",
+					}, {
+						Name: "rack1",
 					}},
-					Size: 3,
+					Size:                     4,
+					RackDistributionStrategy: RackDistributionLastRacks,
 				},
 			},
 			errString: "",
@@ -758,3 +1926,216 @@ func Test_ValidateDatacenterFieldChanges(t *testing.T) {
 		})
 	}
 }
+
+type staticNodeLister []corev1.Node
+
+func (l staticNodeLister) ListNodes() ([]corev1.Node, error) {
+	return l, nil
+}
+
+func Test_WarnResourcesExceedAllocatable(t *testing.T) {
+	makeNode := func(zone string, cpu string, memory string) corev1.Node {
+		return corev1.Node{
+			ObjectMeta: metav1.ObjectMeta{
+				Labels: map[string]string{corev1.LabelZoneFailureDomain: zone},
+			},
+			Status: corev1.NodeStatus{
+				Allocatable: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(cpu),
+					corev1.ResourceMemory: resource.MustParse(memory),
+				},
+			},
+		}
+	}
+
+	dc := CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			Racks: []Rack{
+				{Name: "r1", Zone: "zone-a"},
+				{Name: "r2", Zone: "zone-b"},
+			},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("2"),
+					corev1.ResourceMemory: resource.MustParse("8Gi"),
+				},
+			},
+		},
+	}
+
+	lister := staticNodeLister{
+		makeNode("zone-a", "4", "16Gi"),
+		makeNode("zone-b", "4", "4Gi"),
+		makeNode("zone-b", "1", "16Gi"),
+	}
+
+	assert.Nil(t, WarnResourcesExceedAllocatable(dc, nil))
+	assert.Equal(t,
+		[]string{"no node for rack r2 in zone zone-b has enough allocatable resources for requests of cpu 2 and memory 8Gi"},
+		WarnResourcesExceedAllocatable(dc, lister))
+
+	lister = append(lister, makeNode("zone-b", "2", "8Gi"))
+	assert.Empty(t, WarnResourcesExceedAllocatable(dc, lister))
+}
+
+type staticPodLister []corev1.Pod
+
+func (l staticPodLister) ListPods(namespace string) ([]corev1.Pod, error) {
+	pods := []corev1.Pod{}
+	for _, pod := range l {
+		if pod.Namespace == namespace {
+			pods = append(pods, pod)
+		}
+	}
+	return pods, nil
+}
+
+func Test_WarnUnknownReplaceNodes(t *testing.T) {
+	makePod := func(namespace string, name string, dcName string) corev1.Pod {
+		return corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: namespace,
+				Name:      name,
+				Labels: map[string]string{
+					ClusterLabel:    "cluster1",
+					DatacenterLabel: dcName,
+				},
+			},
+		}
+	}
+
+	dc := CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "test",
+			Name:      "dc1",
+		},
+		Spec: CassandraDatacenterSpec{
+			ClusterName:  "cluster1",
+			ReplaceNodes: []string{"cluster1-dc1-r1-sts-0", "cluster1-dc1-r1-sts-9", "cluster1-dc2-r1-sts-0", "Invalid_Name"},
+		},
+	}
+
+	lister := staticPodLister{
+		makePod("test", "cluster1-dc1-r1-sts-0", "dc1"),
+		makePod("test", "cluster1-dc2-r1-sts-0", "dc2"),
+		makePod("other", "cluster1-dc1-r1-sts-9", "dc1"),
+	}
+
+	assert.Nil(t, WarnUnknownReplaceNodes(dc, nil))
+	assert.Equal(t, []string{
+		"replaceNodes entry cluster1-dc1-r1-sts-9 is not a pod of the datacenter and will never be replaced",
+		"replaceNodes entry cluster1-dc2-r1-sts-0 is not a pod of the datacenter and will never be replaced",
+	}, WarnUnknownReplaceNodes(dc, lister))
+}
+
+func Test_GetValidationWarnings_sharedRackZones(t *testing.T) {
+	dc := CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			Racks: []Rack{{Name: "r1", Zone: "zone-a"}, {Name: "r2", Zone: "zone-a"}},
+		},
+	}
+	assert.Equal(t, []string{"racks r1, r2 share zone zone-a"}, GetValidationWarnings(dc))
+
+	dc.Spec.RequireDistinctRackZones = true
+	assert.Empty(t, GetValidationWarnings(dc))
+}
+
+func Test_validateSuperuserConfigChange(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "exampleDC",
+		},
+		Spec: CassandraDatacenterSpec{
+			ServerType:          "cassandra",
+			ServerVersion:       "3.11.7",
+			SuperuserSecretName: "my-superuser",
+			Users:               []CassandraUser{{SecretName: "my-superuser", Superuser: true}},
+		},
+	}
+	message := "users lists the superuserSecretName secret my-superuser, which the operator already upserts"
+	errString := "CassandraDatacenter write rejected, attempted to use conflicting superuser configuration, " + message
+
+	// A new datacenter is rejected, with ValidateStructured reporting the same error
+	assert.EqualError(t, dc.ValidateCreate(), errString)
+	assert.Contains(t, dc.ValidateStructured(), ValidationIssue{
+		FieldPath: "spec.users",
+		Severity:  ValidationSeverityError,
+		Message:   "use conflicting superuser configuration, " + message,
+	})
+
+	// An existing datacenter keeps its configuration until the superuser fields change
+	dc.Status.SuperUserUpserted = metav1.Now()
+	assert.Contains(t, dc.ValidateStructured(), ValidationIssue{
+		FieldPath: "spec.users",
+		Severity:  ValidationSeverityWarning,
+		Message:   message,
+	})
+	assert.Contains(t, GetValidationWarnings(*dc), message)
+
+	oldDc := dc.DeepCopy()
+	dc.Spec.Size = 3
+	assert.NoError(t, dc.ValidateUpdate(oldDc))
+
+	dc.Spec.Users = append(dc.Spec.Users, CassandraUser{SecretName: "app-user"})
+	assert.EqualError(t, dc.ValidateUpdate(oldDc), errString)
+}
+
+func Test_ValidateStructured(t *testing.T) {
+	dc := &CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: CassandraDatacenterSpec{
+			ServerType:    "cassandra",
+			ServerVersion: "2.2.0",
+			ServerImage:   "cassandra",
+			LogLevel:      "VERBOSE",
+			Racks:         []Rack{{Name: "r1", Zone: "zone-a"}, {Name: "r2", Zone: "zone-a"}},
+		},
+	}
+
+	assert.Equal(t, []ValidationIssue{
+		{
+			FieldPath: "spec.serverVersion",
+			Severity:  ValidationSeverityError,
+			Message:   "use unsupported Cassandra version '2.2.0'",
+		},
+		{
+			FieldPath: "spec.logLevel",
+			Severity:  ValidationSeverityError,
+			Message:   "use log level 'VERBOSE', expected one of ERROR, WARN, INFO, DEBUG, TRACE",
+		},
+		{
+			FieldPath: "spec.racks",
+			Severity:  ValidationSeverityWarning,
+			Message:   "racks r1, r2 share zone zone-a",
+		},
+		{
+			FieldPath: "spec.serverImage",
+			Severity:  ValidationSeverityWarning,
+			Message:   "serverImage 'cassandra' has no tag or digest and pulls latest",
+		},
+	}, dc.ValidateStructured())
+
+	assert.EqualError(t, ValidateSingleDatacenter(*dc),
+		"CassandraDatacenter write rejected, attempted to use unsupported Cassandra version '2.2.0'")
+	assert.Equal(t, ValidateSingleDatacenter(*dc), dc.ValidateCreate())
+
+	dc.Spec.ServerVersion = "3.11.7"
+	dc.Spec.LogLevel = ""
+	assert.NoError(t, dc.ValidateCreate())
+}
+
+func Test_WarnObjectSize(t *testing.T) {
+	dc := CassandraDatacenter{
+		Spec: CassandraDatacenterSpec{
+			Config: json.RawMessage(`{"cassandra-yaml":{"comment":"` + strings.Repeat("x", 4096) + `"}}`),
+		},
+	}
+
+	assert.Nil(t, WarnObjectSize(dc, 0))
+	assert.Empty(t, WarnObjectSize(dc, ObjectSizeWarningBytes))
+	assert.Equal(t, []string{
+		"the datacenter is about 4 KiB serialized, 4 KiB of which is config, approaching the etcd object size limit of 1536 KiB, consider moving the config to a configSecret",
+	}, WarnObjectSize(dc, 2048))
+}
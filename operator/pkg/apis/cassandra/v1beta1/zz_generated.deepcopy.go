@@ -50,6 +50,23 @@ func (in AdditionalVolumesSlice) DeepCopy() AdditionalVolumesSlice {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CQLConnectionInfo) DeepCopyInto(out *CQLConnectionInfo) {
+	*out = *in
+	out.SuperuserSecret = in.SuperuserSecret
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CQLConnectionInfo.
+func (in *CQLConnectionInfo) DeepCopy() *CQLConnectionInfo {
+	if in == nil {
+		return nil
+	}
+	out := new(CQLConnectionInfo)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CassandraDatacenter) DeepCopyInto(out *CassandraDatacenter) {
 	*out = *in
@@ -170,6 +187,13 @@ func (in *CassandraDatacenterSpec) DeepCopyInto(out *CassandraDatacenterSpec) {
 		*out = new(v1.PodTemplateSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.AdditionalPodAnnotations != nil {
+		in, out := &in.AdditionalPodAnnotations, &out.AdditionalPodAnnotations
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Users != nil {
 		in, out := &in.Users, &out.Users
 		*out = make([]CassandraUser, len(*in))
@@ -185,12 +209,46 @@ func (in *CassandraDatacenterSpec) DeepCopyInto(out *CassandraDatacenterSpec) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.EnvFrom != nil {
+		in, out := &in.EnvFrom, &out.EnvFrom
+		*out = make([]v1.EnvFromSource, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Durability != nil {
+		in, out := &in.Durability, &out.Durability
+		*out = new(DurabilityConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.RequestTimeouts != nil {
+		in, out := &in.RequestTimeouts, &out.RequestTimeouts
+		*out = new(RequestTimeoutsConfig)
+		**out = **in
+	}
+	if in.MemtablesAndCaches != nil {
+		in, out := &in.MemtablesAndCaches, &out.MemtablesAndCaches
+		*out = new(MemtablesAndCachesConfig)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Reaper != nil {
 		in, out := &in.Reaper, &out.Reaper
 		*out = new(ReaperConfig)
 		(*in).DeepCopyInto(*out)
 	}
 	in.AdditionalServiceConfig.DeepCopyInto(&out.AdditionalServiceConfig)
+	if in.AllPodsServicePublishNotReadyAddresses != nil {
+		in, out := &in.AllPodsServicePublishNotReadyAddresses, &out.AllPodsServicePublishNotReadyAddresses
+		*out = new(bool)
+		**out = **in
+	}
+	if in.SystemAuthReplication != nil {
+		in, out := &in.SystemAuthReplication, &out.SystemAuthReplication
+		*out = make(map[string]int32, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	if in.Tolerations != nil {
 		in, out := &in.Tolerations, &out.Tolerations
 		*out = make([]v1.Toleration, len(*in))
@@ -338,6 +396,43 @@ func (in *DseWorkloads) DeepCopy() *DseWorkloads {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DurabilityConfig) DeepCopyInto(out *DurabilityConfig) {
+	*out = *in
+	if in.HintedHandoffEnabled != nil {
+		in, out := &in.HintedHandoffEnabled, &out.HintedHandoffEnabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DurabilityConfig.
+func (in *DurabilityConfig) DeepCopy() *DurabilityConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(DurabilityConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ExpectedStatefulSet) DeepCopyInto(out *ExpectedStatefulSet) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ExpectedStatefulSet.
+func (in *ExpectedStatefulSet) DeepCopy() *ExpectedStatefulSet {
+	if in == nil {
+		return nil
+	}
+	out := new(ExpectedStatefulSet)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ManagementApiAuthConfig) DeepCopyInto(out *ManagementApiAuthConfig) {
 	*out = *in
@@ -396,6 +491,52 @@ func (in *ManagementApiAuthManualConfig) DeepCopy() *ManagementApiAuthManualConf
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MemtablesAndCachesConfig) DeepCopyInto(out *MemtablesAndCachesConfig) {
+	*out = *in
+	if in.MemtableHeapSpaceMb != nil {
+		in, out := &in.MemtableHeapSpaceMb, &out.MemtableHeapSpaceMb
+		*out = new(int64)
+		**out = **in
+	}
+	if in.MemtableOffheapSpaceMb != nil {
+		in, out := &in.MemtableOffheapSpaceMb, &out.MemtableOffheapSpaceMb
+		*out = new(int64)
+		**out = **in
+	}
+	if in.KeyCacheSizeMb != nil {
+		in, out := &in.KeyCacheSizeMb, &out.KeyCacheSizeMb
+		*out = new(int64)
+		**out = **in
+	}
+	if in.RowCacheSizeMb != nil {
+		in, out := &in.RowCacheSizeMb, &out.RowCacheSizeMb
+		*out = new(int64)
+		**out = **in
+	}
+	if in.CounterCacheSizeMb != nil {
+		in, out := &in.CounterCacheSizeMb, &out.CounterCacheSizeMb
+		*out = new(int64)
+		**out = **in
+	}
+	if in.FileCacheSizeMb != nil {
+		in, out := &in.FileCacheSizeMb, &out.FileCacheSizeMb
+		*out = new(int64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MemtablesAndCachesConfig.
+func (in *MemtablesAndCachesConfig) DeepCopy() *MemtablesAndCachesConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(MemtablesAndCachesConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *NetworkingConfig) DeepCopyInto(out *NetworkingConfig) {
 	*out = *in
@@ -443,6 +584,11 @@ func (in *Rack) DeepCopyInto(out *Rack) {
 			(*out)[key] = val
 		}
 	}
+	if in.Resources != nil {
+		in, out := &in.Resources, &out.Resources
+		*out = new(v1.ResourceRequirements)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -456,6 +602,27 @@ func (in *Rack) DeepCopy() *Rack {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RackUpdateStrategy) DeepCopyInto(out *RackUpdateStrategy) {
+	*out = *in
+	if in.Partition != nil {
+		in, out := &in.Partition, &out.Partition
+		*out = new(int32)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RackUpdateStrategy.
+func (in *RackUpdateStrategy) DeepCopy() *RackUpdateStrategy {
+	if in == nil {
+		return nil
+	}
+	out := new(RackUpdateStrategy)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReaperConfig) DeepCopyInto(out *ReaperConfig) {
 	*out = *in
@@ -473,6 +640,22 @@ func (in *ReaperConfig) DeepCopy() *ReaperConfig {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RequestTimeoutsConfig) DeepCopyInto(out *RequestTimeoutsConfig) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RequestTimeoutsConfig.
+func (in *RequestTimeoutsConfig) DeepCopy() *RequestTimeoutsConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RequestTimeoutsConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceConfig) DeepCopyInto(out *ServiceConfig) {
 	*out = *in
@@ -524,6 +707,45 @@ func (in *ServiceConfigAdditions) DeepCopy() *ServiceConfigAdditions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ServiceMonitorSpec) DeepCopyInto(out *ServiceMonitorSpec) {
+	*out = *in
+	if in.Selector != nil {
+		in, out := &in.Selector, &out.Selector
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ServiceMonitorSpec.
+func (in *ServiceMonitorSpec) DeepCopy() *ServiceMonitorSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ServiceMonitorSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SpecFieldChange) DeepCopyInto(out *SpecFieldChange) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SpecFieldChange.
+func (in *SpecFieldChange) DeepCopy() *SpecFieldChange {
+	if in == nil {
+		return nil
+	}
+	out := new(SpecFieldChange)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *StorageConfig) DeepCopyInto(out *StorageConfig) {
 	*out = *in
@@ -551,3 +773,19 @@ func (in *StorageConfig) DeepCopy() *StorageConfig {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ValidationIssue) DeepCopyInto(out *ValidationIssue) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ValidationIssue.
+func (in *ValidationIssue) DeepCopy() *ValidationIssue {
+	if in == nil {
+		return nil
+	}
+	out := new(ValidationIssue)
+	in.DeepCopyInto(out)
+	return out
+}
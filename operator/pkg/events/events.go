@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/go-logr/logr"
+	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/record"
 )
@@ -29,8 +30,28 @@ const (
 	ReplacingNode                     string = "ReplacingNode"
 	StartingCassandraAndReplacingNode string = "StartingCassandraAndReplacingNode"
 	StartingCassandra                 string = "StartingCassandra"
+	ValidationFailed                  string = "ValidationFailed"
+	ReconcileFailed                   string = "ReconcileFailed"
+	ConfigRenderingFailed             string = "ConfigRenderingFailed"
 )
 
+// DatacenterMessage formats an event message prefixed with the cluster and datacenter
+// names, e.g. "cluster1/dc1: Created users", so that event text stays consistent and
+// easy to grep for across datacenters.
+func DatacenterMessage(dc *api.CassandraDatacenter, messageFmt string, args ...interface{}) string {
+	return RackMessage(dc, "", messageFmt, args...)
+}
+
+// RackMessage formats an event message like DatacenterMessage, additionally including the
+// rack name in the prefix when it is non-empty, e.g. "cluster1/dc1/r1: Started Cassandra".
+func RackMessage(dc *api.CassandraDatacenter, rackName string, messageFmt string, args ...interface{}) string {
+	prefix := fmt.Sprintf("%s/%s", dc.Spec.ClusterName, dc.Name)
+	if rackName != "" {
+		prefix = fmt.Sprintf("%s/%s", prefix, rackName)
+	}
+	return fmt.Sprintf("%s: %s", prefix, fmt.Sprintf(messageFmt, args...))
+}
+
 type LoggingEventRecorder struct {
 	record.EventRecorder
 	ReqLogger logr.InfoLogger
@@ -6,6 +6,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/assert"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/tools/record"
 	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
 )
@@ -67,3 +68,20 @@ func TestLoggingEventRecorder(t *testing.T) {
 			keysAndValues)
 	}
 }
+
+func TestRackMessage(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName: "cluster1",
+		},
+	}
+
+	assert.Equal(t, "cluster1/dc1: Created users", DatacenterMessage(dc, "Created users"))
+	assert.Equal(t, "cluster1/dc1/r1: Started Cassandra for pod p1",
+		RackMessage(dc, "r1", "Started Cassandra for pod %s", "p1"))
+	assert.Equal(t, "cluster1/dc1: Started Cassandra for pod p1",
+		RackMessage(dc, "", "Started Cassandra for pod %s", "p1"))
+}
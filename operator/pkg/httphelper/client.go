@@ -358,7 +358,7 @@ func (client *NodeMgmtClient) CallDecommissionNodeEndpoint(pod *corev1.Pod) erro
 func callNodeMgmtEndpoint(client *NodeMgmtClient, request nodeMgmtRequest, contentType string) ([]byte, error) {
 	client.Log.Info("client::callNodeMgmtEndpoint")
 
-	url := fmt.Sprintf("%s://%s:8080%s", client.Protocol, request.host, request.endpoint)
+	url := fmt.Sprintf("%s://%s:%d%s", client.Protocol, request.host, ManagementApiPort, request.endpoint)
 
 	var reqBody io.Reader
 	if len(request.body) > 0 {
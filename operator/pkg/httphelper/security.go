@@ -20,6 +20,9 @@ import (
 )
 
 const (
+	// ManagementApiPort is the port the management API listens on in the server container
+	ManagementApiPort = 8080
+
 	WgetNodeDrainEndpoint = "localhost:8080/api/v0/ops/node/drain"
 	// TODO: Get endpoint from configured HTTPGet probe
 	livenessEndpoint = "localhost:8080/api/v0/probes/liveness"
@@ -40,6 +43,23 @@ func GetManagementApiProtocol(dc *api.CassandraDatacenter) (string, error) {
 	return provider.GetProtocol(), nil
 }
 
+// GetManagementApiEndpoints returns the base URL of the management API of every pod
+// expected for the datacenter, keyed by pod name. The pods are addressed by their DNS
+// names under the service governing the statefulsets.
+func GetManagementApiEndpoints(dc *api.CassandraDatacenter) (map[string]string, error) {
+	protocol, err := GetManagementApiProtocol(dc)
+	if err != nil {
+		return nil, err
+	}
+
+	endpoints := map[string]string{}
+	for _, podName := range dc.GetPodNames() {
+		endpoints[podName] = fmt.Sprintf("%s://%s.%s.%s.svc:%d",
+			protocol, podName, dc.GetStatefulSetServiceName(), dc.Namespace, ManagementApiPort)
+	}
+	return endpoints, nil
+}
+
 func BuildManagementApiHttpClient(dc *api.CassandraDatacenter, client client.Client, ctx context.Context) (HttpClient, error) {
 	provider, err := BuildManagmenetApiSecurityProvider(dc)
 	if err != nil {
@@ -133,6 +153,8 @@ func (provider *InsecureManagementApiSecurityProvider) ValidateConfig(client cli
 type ManualManagementApiSecurityProvider struct {
 	Namespace string
 	Config    *api.ManagementApiAuthManualConfig
+	// ContainerName is the name of the pod container running the management API
+	ContainerName string
 }
 
 func buildManualApiSecurityProvider(dc *api.CassandraDatacenter) (ManagementApiSecurityProvider, error) {
@@ -140,6 +162,7 @@ func buildManualApiSecurityProvider(dc *api.CassandraDatacenter) (ManagementApiS
 		provider := &ManualManagementApiSecurityProvider{}
 		provider.Config = dc.Spec.ManagementApiAuth.Manual
 		provider.Namespace = dc.ObjectMeta.Namespace
+		provider.ContainerName = dc.GetManagementApiContainerName()
 		return provider, nil
 	}
 	return nil, nil
@@ -222,13 +245,13 @@ func (provider *ManualManagementApiSecurityProvider) AddServerSecurity(pod *core
 	// find the container
 	var container *corev1.Container = nil
 	for i := range pod.Spec.Containers {
-		if pod.Spec.Containers[i].Name == "cassandra" {
+		if pod.Spec.Containers[i].Name == provider.ContainerName {
 			container = &pod.Spec.Containers[i]
 		}
 	}
 
 	if container == nil {
-		return fmt.Errorf("Could not find cassandra container")
+		return fmt.Errorf("Could not find %s container", provider.ContainerName)
 	}
 
 	// Add volume containing certificates
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"testing"
 
+	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -98,3 +99,27 @@ func Test_validatePrivateKey(t *testing.T) {
 		t, 1, len(errs),
 		"Should consider an empty key as an invalid key")
 }
+
+func Test_GetManagementApiEndpoints(t *testing.T) {
+	dc := &api.CassandraDatacenter{}
+	dc.Name = "dc1"
+	dc.Namespace = "ns"
+	dc.Spec.ClusterName = "c1"
+	dc.Spec.Size = 2
+	dc.Spec.Racks = []api.Rack{{Name: "r1"}, {Name: "r2"}}
+	dc.Spec.ManagementApiAuth.Insecure = &api.ManagementApiAuthInsecureConfig{}
+
+	endpoints, err := GetManagementApiEndpoints(dc)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]string{
+		"c1-dc1-r1-sts-0": "http://c1-dc1-r1-sts-0.c1-dc1-all-pods-service.ns.svc:8080",
+		"c1-dc1-r2-sts-0": "http://c1-dc1-r2-sts-0.c1-dc1-all-pods-service.ns.svc:8080",
+	}, endpoints)
+
+	dc.Spec.ManagementApiAuth.Manual = &api.ManagementApiAuthManualConfig{
+		ClientSecretName: "client-secret",
+		ServerSecretName: "server-secret",
+	}
+	_, err = GetManagementApiEndpoints(dc)
+	assert.Error(t, err)
+}
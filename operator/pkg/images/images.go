@@ -23,8 +23,20 @@ const (
 	ValidDseVersionRegexp                 = "6\\.8\\.\\d+"
 	ValidOssVersionRegexp                 = "(3\\.11\\.\\d+)|(4\\.0\\.\\d+)"
 	UbiImageSuffix                        = "-ubi7"
+
+	// validImageReferenceRegexp follows the docker reference grammar,
+	// [domain[:port]/]path[:tag][@digest], where path components are lower case
+	validImageReferenceRegexp = `^` +
+		`(?:(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9])(?:\.(?:[a-zA-Z0-9]|[a-zA-Z0-9][a-zA-Z0-9-]*[a-zA-Z0-9]))*(?::[0-9]+)?/)?` +
+		`[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|[-]*)[a-z0-9]+)*)*` +
+		`(?::[\w][\w.-]{0,127})?` +
+		`(?:@[A-Za-z][A-Za-z0-9]*(?:[-_+.][A-Za-z][A-Za-z0-9]*)*:[0-9a-fA-F]{32,})?` +
+		`$`
+	maxImageNameLength = 255
 )
 
+var validImageReference = regexp.MustCompile(validImageReferenceRegexp)
+
 // How to add new images:
 //
 // 1. Add a new Image enum value below
@@ -148,6 +160,41 @@ func IsOssVersionSupported(version string) bool {
 	return validVersions.MatchString(version)
 }
 
+// IsValidImageReference returns whether the image can be parsed as a docker
+// reference. The tag is optional, in which case "latest" is pulled.
+func IsValidImageReference(image string) bool {
+	name := image
+	if i := strings.Index(name, "@"); i >= 0 {
+		name = name[:i]
+	}
+	if i := strings.LastIndex(name, ":"); i >= 0 && !strings.Contains(name[i:], "/") {
+		name = name[:i]
+	}
+	if len(name) > maxImageNameLength {
+		return false
+	}
+	return validImageReference.MatchString(image)
+}
+
+// HasImageTagOrDigest returns whether the image reference pins a tag or a digest, rather
+// than pulling "latest"
+func HasImageTagOrDigest(image string) bool {
+	if strings.Contains(image, "@") {
+		return true
+	}
+	lastComponent := image[strings.LastIndex(image, "/")+1:]
+	return strings.Contains(lastComponent, ":")
+}
+
+// DefaultPullPolicy returns the pull policy Kubernetes defaults to for the image, which is
+// Always for an image pulling "latest" and IfNotPresent otherwise
+func DefaultPullPolicy(image string) corev1.PullPolicy {
+	if !HasImageTagOrDigest(image) || strings.HasSuffix(image, ":latest") {
+		return corev1.PullAlways
+	}
+	return corev1.PullIfNotPresent
+}
+
 func stripRegistry(image string) string {
 	comps := strings.Split(image, "/")
 
@@ -236,31 +283,52 @@ func GetCassandraImage(serverType, version string) (string, error) {
 
 	if !found {
 		// For fallback images, just return the image name directly
-		fallbackImageName := ""
-
 		if serverType == "dse" {
 			if !IsDseVersionSupported(version) {
 				return "", fmt.Errorf("server 'dse' and version '%s' do not work together", version)
 			}
-			fallbackImageName = fmt.Sprintf("datastax/dse-server:%s", version)
 		} else {
 			if !IsOssVersionSupported(version) {
 				return "", fmt.Errorf("server 'cassandra' and version '%s' do not work together", version)
 			}
-			// We will fall back to the "mutable" cassandra image without the explicit mgmt-api version
-			fallbackImageName = fmt.Sprintf("datastax/cassandra-mgmtapi:%s", version)
 		}
 
-		if shouldUseUBI() {
-			return fmt.Sprintf("%s%s", fallbackImageName, UbiImageSuffix), nil
-		}
-
-		return fallbackImageName, nil
+		return getFallbackCassandraImage(serverType, version), nil
 	}
 
 	return GetImage(imageKey), nil
 }
 
+// getFallbackCassandraImage returns the image name of a server version without a pinned
+// image
+func getFallbackCassandraImage(serverType, version string) string {
+	fallbackImageName := fmt.Sprintf("datastax/dse-server:%s", version)
+	if serverType == "cassandra" {
+		// We will fall back to the "mutable" cassandra image without the explicit mgmt-api version
+		fallbackImageName = fmt.Sprintf("datastax/cassandra-mgmtapi:%s", version)
+	}
+
+	if shouldUseUBI() {
+		return fmt.Sprintf("%s%s", fallbackImageName, UbiImageSuffix)
+	}
+
+	return fallbackImageName
+}
+
+// GetCassandraImageBestEffort returns the image of GetCassandraImage and true, without
+// failing for server versions it rejects. For those it returns the image name the version
+// would have and false, or an empty name when the server type or version is unknown.
+func GetCassandraImageBestEffort(serverType, version string) (string, bool) {
+	if image, err := GetCassandraImage(serverType, version); err == nil {
+		return image, true
+	}
+
+	if version == "" || (serverType != "dse" && serverType != "cassandra") {
+		return "", false
+	}
+	return getFallbackCassandraImage(serverType, version), false
+}
+
 func GetConfigBuilderImage() string {
 	if shouldUseUBI() {
 		return GetImage(UBIConfigBuilder)
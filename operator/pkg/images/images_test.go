@@ -11,6 +11,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
 )
 
 func tempSetEnv(name, value string) (func(), error) {
@@ -82,3 +83,71 @@ func Test_CalculateDockerImageRunsAsCassandra(t *testing.T) {
 		assert.Equal(t, got, tt.want, fmt.Sprintf("Version: %s should not have returned %v", tt.version, got))
 	}
 }
+
+func TestIsValidImageReference(t *testing.T) {
+	valid := []string{
+		"cassandra",
+		"cassandra:3.11.7",
+		"datastax/cassandra-mgmtapi-3_11_7:v0.1.5",
+		"localhost:5000/datastax/cassandra-mgmtapi",
+		"registry.example.com/team/cass_operator/cassandra:4.0.0-ubi7",
+		"datastax/dse-server@sha256:e4a2b0bb13c7b7b9d4c5c2ddf7d0d6d5e3c6b0a3a9a1c0c6c4d2f8b5e6a7d8c9",
+		"datastax/dse-server:6.8.4@sha256:e4a2b0bb13c7b7b9d4c5c2ddf7d0d6d5e3c6b0a3a9a1c0c6c4d2f8b5e6a7d8c9",
+	}
+	for _, image := range valid {
+		assert.True(t, IsValidImageReference(image), "expected %s to be valid", image)
+	}
+
+	invalid := []string{
+		"",
+		"datastax/Cassandra:3.11.7",
+		"datastax/cassandra::3.11.7",
+		"datastax/cassandra:3.11.7:latest",
+		"datastax/cassandra 3.11.7",
+		"datastax//cassandra",
+		"datastax/cassandra:",
+		"datastax/cassandra@sha256:abc",
+		"/datastax/cassandra",
+		"datastax/" + strings.Repeat("a", 260),
+	}
+	for _, image := range invalid {
+		assert.False(t, IsValidImageReference(image), "expected %s to be invalid", image)
+	}
+}
+
+func TestHasImageTagOrDigest(t *testing.T) {
+	assert.True(t, HasImageTagOrDigest("datastax/cass-config-builder:1.0.3"))
+	assert.True(t, HasImageTagOrDigest("localhost:5000/datastax/cass-config-builder:1.0.3"))
+	assert.True(t, HasImageTagOrDigest("datastax/cass-config-builder@sha256:e4a2b0bb13c7b7b9d4c5c2ddf7d0d6d5e3c6b0a3a9a1c0c6c4d2f8b5e6a7d8c9"))
+	assert.False(t, HasImageTagOrDigest("datastax/cass-config-builder"))
+	assert.False(t, HasImageTagOrDigest("localhost:5000/datastax/cass-config-builder"))
+}
+
+func TestDefaultPullPolicy(t *testing.T) {
+	assert.Equal(t, corev1.PullIfNotPresent, DefaultPullPolicy("datastax/cass-config-builder:1.0.3"))
+	assert.Equal(t, corev1.PullIfNotPresent, DefaultPullPolicy("datastax/cass-config-builder@sha256:e4a2b0bb13c7b7b9d4c5c2ddf7d0d6d5e3c6b0a3a9a1c0c6c4d2f8b5e6a7d8c9"))
+	assert.Equal(t, corev1.PullAlways, DefaultPullPolicy("datastax/cass-config-builder:latest"))
+	assert.Equal(t, corev1.PullAlways, DefaultPullPolicy("localhost:5000/datastax/cass-config-builder"))
+}
+
+func TestGetCassandraImageBestEffort(t *testing.T) {
+	image, resolved := GetCassandraImageBestEffort("cassandra", "3.11.7")
+	assert.True(t, resolved)
+	assert.Equal(t, GetImage(Cassandra_3_11_7), image)
+
+	image, resolved = GetCassandraImageBestEffort("cassandra", "2.2.0")
+	assert.False(t, resolved)
+	assert.Equal(t, "datastax/cassandra-mgmtapi:2.2.0", image)
+
+	image, resolved = GetCassandraImageBestEffort("dse", "5.1.0")
+	assert.False(t, resolved)
+	assert.Equal(t, "datastax/dse-server:5.1.0", image)
+
+	image, resolved = GetCassandraImageBestEffort("cassandra", "")
+	assert.False(t, resolved)
+	assert.Equal(t, "", image)
+
+	image, resolved = GetCassandraImageBestEffort("scylla", "4.0.0")
+	assert.False(t, resolved)
+	assert.Equal(t, "", image)
+}
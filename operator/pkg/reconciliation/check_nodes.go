@@ -150,11 +150,11 @@ func (rc *ReconciliationContext) GetDCPods() []*corev1.Pod {
 }
 
 func (rc *ReconciliationContext) GetNotReadyPodsBootstrappedInDC() []*corev1.Pod {
-	return findAllPodsNotReadyAndPotentiallyBootstrapped(rc.dcPods, rc.Datacenter.Status.NodeStatuses)
+	return findAllPodsNotReadyAndPotentiallyBootstrapped(rc.Datacenter, rc.dcPods, rc.Datacenter.Status.NodeStatuses)
 }
 
 func (rc *ReconciliationContext) GetAllPodsNotReadyInDC() []*corev1.Pod {
-	return findAllPodsNotReady(rc.dcPods)
+	return findAllPodsNotReady(rc.Datacenter, rc.dcPods)
 }
 
 func (rc *ReconciliationContext) GetPodPVCs(pod *corev1.Pod) ([]*corev1.PersistentVolumeClaim, error) {
@@ -207,7 +207,7 @@ func (rc *ReconciliationContext) GetInProgressNodeReplacements() []string {
 }
 
 func (rc *ReconciliationContext) RemovePod(pod *corev1.Pod) error {
-	if isMgmtApiRunning(pod) {
+	if isMgmtApiRunning(rc.Datacenter, pod) {
 		err := rc.NodeMgmtClient.CallDrainEndpoint(pod)
 		if err != nil {
 			rc.ReqLogger.Error(err, "error during cassandra node drain",
@@ -25,7 +25,8 @@ import (
 const (
 	DefaultTerminationGracePeriodSeconds = 120
 	ServerConfigContainerName            = "server-config-init"
-	CassandraContainerName               = "cassandra"
+	ListenInterfaceConfigContainerName   = "server-config-listen-interface"
+	CassandraContainerName               = api.ServerContainerName
 	PvcName                              = "server-data"
 	SystemLoggerContainerName            = "server-system-logger"
 )
@@ -64,36 +65,76 @@ func calculateNodeAffinity(labels map[string]string) *corev1.NodeAffinity {
 	}
 }
 
-// calculatePodAntiAffinity provides a way to keep the db pods of a statefulset away from other db pods
-func calculatePodAntiAffinity(allowMultipleNodesPerWorker bool) *corev1.PodAntiAffinity {
+// calculatePodAntiAffinity provides a way to keep the db pods of a statefulset away from the
+// other db pods with the given labels, those of its datacenter or of its whole cluster, or
+// from all db pods when there are no labels. The rack label only selects db pods.
+func calculatePodAntiAffinity(allowMultipleNodesPerWorker bool, topologyKey string, podLabels map[string]string) *corev1.PodAntiAffinity {
 	if allowMultipleNodesPerWorker {
 		return nil
 	}
+
+	selector := &metav1.LabelSelector{
+		MatchExpressions: []metav1.LabelSelectorRequirement{
+			{
+				Key:      api.ClusterLabel,
+				Operator: metav1.LabelSelectorOpExists,
+			},
+			{
+				Key:      api.DatacenterLabel,
+				Operator: metav1.LabelSelectorOpExists,
+			},
+			{
+				Key:      api.RackLabel,
+				Operator: metav1.LabelSelectorOpExists,
+			},
+		},
+	}
+	if len(podLabels) > 0 {
+		selector = &metav1.LabelSelector{
+			MatchLabels: podLabels,
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{
+					Key:      api.RackLabel,
+					Operator: metav1.LabelSelectorOpExists,
+				},
+			},
+		}
+	}
+
 	return &corev1.PodAntiAffinity{
 		RequiredDuringSchedulingIgnoredDuringExecution: []corev1.PodAffinityTerm{
 			{
-				LabelSelector: &metav1.LabelSelector{
-					MatchExpressions: []metav1.LabelSelectorRequirement{
-						{
-							Key:      api.ClusterLabel,
-							Operator: metav1.LabelSelectorOpExists,
-						},
-						{
-							Key:      api.DatacenterLabel,
-							Operator: metav1.LabelSelectorOpExists,
-						},
-						{
-							Key:      api.RackLabel,
-							Operator: metav1.LabelSelectorOpExists,
-						},
-					},
-				},
-				TopologyKey: "kubernetes.io/hostname",
+				LabelSelector: selector,
+				TopologyKey:   topologyKey,
 			},
 		},
 	}
 }
 
+// calculateAffinity returns the affinity of the server pods. With relaxed scheduling the
+// anti-affinity between server pods is only preferred, so that pods can share a worker
+// when there are not enough of them.
+func calculateAffinity(dc *api.CassandraDatacenter, nodeAffinityLabels map[string]string) *corev1.Affinity {
+	affinity := &corev1.Affinity{}
+	affinity.NodeAffinity = calculateNodeAffinity(nodeAffinityLabels)
+	affinity.PodAntiAffinity = calculatePodAntiAffinity(dc.Spec.AllowMultipleNodesPerWorker, dc.GetAntiAffinityTopologyKey(), dc.GetAntiAffinityLabels())
+
+	if dc.Spec.RelaxedScheduling && affinity.PodAntiAffinity != nil {
+		preferred := []corev1.WeightedPodAffinityTerm{}
+		for _, term := range affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution {
+			preferred = append(preferred, corev1.WeightedPodAffinityTerm{
+				Weight:          100,
+				PodAffinityTerm: term,
+			})
+		}
+		affinity.PodAntiAffinity = &corev1.PodAntiAffinity{
+			PreferredDuringSchedulingIgnoredDuringExecution: preferred,
+		}
+	}
+
+	return affinity
+}
+
 func selectorFromFieldPath(fieldPath string) *corev1.EnvVarSource {
 	return &corev1.EnvVarSource{
 		FieldRef: &corev1.ObjectFieldSelector{
@@ -190,6 +231,32 @@ outerLoop:
 	return out
 }
 
+// combineReadinessGateSlices returns the readiness gates of both slices, keeping only the
+// first gate of each condition type
+func combineReadinessGateSlices(defaults []corev1.PodReadinessGate, overrides []corev1.PodReadinessGate) []corev1.PodReadinessGate {
+	var out []corev1.PodReadinessGate
+	seen := map[corev1.PodConditionType]bool{}
+	for _, gates := range [][]corev1.PodReadinessGate{defaults, overrides} {
+		for _, gate := range gates {
+			if !seen[gate.ConditionType] {
+				seen[gate.ConditionType] = true
+				out = append(out, gate)
+			}
+		}
+	}
+	return out
+}
+
+// GetPodReadinessGates returns the readiness gates of the pod template, which are the ones
+// of the PodTemplateSpec without duplicate condition types
+func GetPodReadinessGates(dc *api.CassandraDatacenter) []corev1.PodReadinessGate {
+	var userGates []corev1.PodReadinessGate
+	if dc.Spec.PodTemplateSpec != nil {
+		userGates = dc.Spec.PodTemplateSpec.Spec.ReadinessGates
+	}
+	return combineReadinessGateSlices(nil, userGates)
+}
+
 func generateStorageConfigVolumesMount(cc *api.CassandraDatacenter) []corev1.VolumeMount {
 	var vms []corev1.VolumeMount
 	for _, storage := range cc.Spec.StorageConfig.AdditionalVolumes {
@@ -281,15 +348,99 @@ func buildInitContainers(dc *api.CassandraDatacenter, rackName string, baseTempl
 
 	}
 
+	// Left to the Kubernetes default unless set, which the helper mirrors
+	if serverCfg.ImagePullPolicy == "" && dc.Spec.ConfigBuilderImagePullPolicy != "" {
+		serverCfg.ImagePullPolicy = dc.GetConfigBuilderImagePullPolicy()
+	}
+
 	serverCfgMount := corev1.VolumeMount{
 		Name:      "server-config",
-		MountPath: "/config",
+		MountPath: dc.GetConfigMountPath(),
 	}
 
 	serverCfg.VolumeMounts = combineVolumeMountSlices([]corev1.VolumeMount{serverCfgMount}, serverCfg.VolumeMounts)
 
 	serverCfg.Resources = *getResourcesOrDefault(&dc.Spec.ConfigBuilderResources, &DefaultsConfigInitContainer)
 
+	envDefaults, err := getConfigBuilderEnvVars(dc, rackName)
+	if err != nil {
+		return err
+	}
+
+	serverCfg.Env = combineEnvSlices(envDefaults, serverCfg.Env)
+
+	if !foundOverrides {
+		// Note that append makes a copy, so we must do this after
+		// serverCfg has been properly set up.
+		baseTemplate.Spec.InitContainers = append(baseTemplate.Spec.InitContainers, *serverCfg)
+	}
+
+	if dc.IsListenInterfaceEnabled() {
+		return buildListenInterfaceConfigContainer(dc, baseTemplate)
+	}
+
+	return nil
+}
+
+// buildListenInterfaceConfigContainer adds the init container which deletes listen_address
+// from the cassandra.yaml rendered by the server-config-init container. The config builder
+// renders the pod address as listen_address, which the server refuses together with
+// listen_interface. POD_IP stays set as the broadcast addresses are rendered from it.
+func buildListenInterfaceConfigContainer(dc *api.CassandraDatacenter, baseTemplate *corev1.PodTemplateSpec) error {
+	container := &corev1.Container{}
+	foundOverrides := false
+
+	for i, c := range baseTemplate.Spec.InitContainers {
+		if c.Name == ListenInterfaceConfigContainerName {
+			foundOverrides = true
+			container = &baseTemplate.Spec.InitContainers[i]
+			break
+		}
+	}
+
+	container.Name = ListenInterfaceConfigContainerName
+
+	// The server image is pulled for the pod anyway and has sed
+	if container.Image == "" {
+		serverImage, err := makeImage(dc)
+		if err != nil {
+			return err
+		}
+		container.Image = serverImage
+	}
+
+	if container.ImagePullPolicy == "" && dc.Spec.ServerImagePullPolicy != "" {
+		container.ImagePullPolicy = dc.GetServerImagePullPolicy()
+	}
+
+	if len(container.Command) == 0 {
+		container.Command = getListenInterfaceConfigCommand(dc.GetConfigMountPath())
+	}
+
+	container.VolumeMounts = combineVolumeMountSlices([]corev1.VolumeMount{{
+		Name:      "server-config",
+		MountPath: dc.GetConfigMountPath(),
+	}}, container.VolumeMounts)
+
+	container.Resources = *getResourcesOrDefault(&dc.Spec.ConfigBuilderResources, &DefaultsConfigInitContainer)
+
+	if !foundOverrides {
+		baseTemplate.Spec.InitContainers = append(baseTemplate.Spec.InitContainers, *container)
+	}
+
+	return nil
+}
+
+// getListenInterfaceConfigCommand returns the command deleting listen_address from the
+// cassandra.yaml in the config directory
+func getListenInterfaceConfigCommand(configPath string) []string {
+	return []string{"sed", "-i", "/^listen_address:/d", configPath + "/cassandra.yaml"}
+}
+
+// getConfigBuilderEnvVars returns the complete list of env vars for the server-config-builder
+// init container. PRODUCT_NAME and PRODUCT_VERSION select the base config for the server type
+// and version, and are followed by the config data env vars from getConfigDataEnVars.
+func getConfigBuilderEnvVars(dc *api.CassandraDatacenter, rackName string) ([]corev1.EnvVar, error) {
 	// Convert the bool to a string for the env var setting
 	useHostIpForBroadcast := "false"
 	if dc.IsNodePortEnabled() {
@@ -298,12 +449,12 @@ func buildInitContainers(dc *api.CassandraDatacenter, rackName string, baseTempl
 
 	configEnvVar, err := getConfigDataEnVars(dc)
 	if err != nil {
-		return errors.Wrap(err, "failed to get config env vars")
+		return nil, errors.Wrap(err, "failed to get config env vars")
 	}
 
 	serverVersion := dc.Spec.ServerVersion
 
-	envDefaults := []corev1.EnvVar{
+	envVars := []corev1.EnvVar{
 		{Name: "POD_IP", ValueFrom: selectorFromFieldPath("status.podIP")},
 		{Name: "HOST_IP", ValueFrom: selectorFromFieldPath("status.hostIP")},
 		{Name: "USE_HOST_IP_FOR_BROADCAST", Value: useHostIpForBroadcast},
@@ -314,19 +465,66 @@ func buildInitContainers(dc *api.CassandraDatacenter, rackName string, baseTempl
 		{Name: "DSE_VERSION", Value: serverVersion},
 	}
 
-	for _, envVar := range configEnvVar {
-		envDefaults = append(envDefaults, envVar)
+	envVars = append(envVars, configEnvVar...)
+
+	return envVars, nil
+}
+
+// getPreStopHandler returns the preStop hook of the cassandra container, which drains the
+// node through the management API, using the protocol and certificates of the management
+// API auth config, so that every pod termination flushes the memtables. It returns nil
+// when DisablePreStopDrain is set.
+func getPreStopHandler(dc *api.CassandraDatacenter) (*corev1.Handler, error) {
+	if dc.Spec.DisablePreStopDrain {
+		return nil, nil
 	}
 
-	serverCfg.Env = combineEnvSlices(envDefaults, serverCfg.Env)
+	action, err := httphelper.GetMgmtApiWgetPostAction(dc, httphelper.WgetNodeDrainEndpoint, "")
+	if err != nil {
+		return nil, err
+	}
+	return &corev1.Handler{
+		Exec: action,
+	}, nil
+}
 
-	if !foundOverrides {
-		// Note that append makes a copy, so we must do this after
-		// serverCfg has been properly set up.
-		baseTemplate.Spec.InitContainers = append(baseTemplate.Spec.InitContainers, *serverCfg)
+// getServerEnvVars returns the env vars the operator sets on the server container. These
+// may not be set through the EnvFrom sources of the datacenter.
+func getServerEnvVars(dc *api.CassandraDatacenter) []corev1.EnvVar {
+	envVars := []corev1.EnvVar{
+		{Name: "DS_LICENSE", Value: "accept"},
+		{Name: "DSE_AUTO_CONF_OFF", Value: "all"},
 	}
 
-	return nil
+	if dc.GetManagementApiMode() == api.ManagementApiModeInContainer {
+		envVars = append(envVars, getManagementApiEnvVars()...)
+	}
+
+	if dc.Spec.ServerType == "dse" && dc.Spec.DseWorkloads != nil {
+		envVars = append(
+			envVars,
+			corev1.EnvVar{Name: "JVM_EXTRA_OPTS", Value: getJvmExtraOpts(dc)})
+	}
+
+	return envVars
+}
+
+// findReservedEnvVars returns the env var names, sorted, which the keys of an EnvFrom source
+// with the given prefix would define and which are also set by the operator
+func findReservedEnvVars(dc *api.CassandraDatacenter, prefix string, keys []string) []string {
+	reserved := map[string]bool{}
+	for _, envVar := range getServerEnvVars(dc) {
+		reserved[envVar.Name] = true
+	}
+
+	found := []string{}
+	for _, key := range keys {
+		if reserved[prefix+key] {
+			found = append(found, prefix+key)
+		}
+	}
+	sort.Strings(found)
+	return found
 }
 
 func getConfigDataEnVars(dc *api.CassandraDatacenter) ([]corev1.EnvVar, error) {
@@ -379,17 +577,77 @@ func makeImage(dc *api.CassandraDatacenter) (string, error) {
 	return dc.GetServerImage(), nil
 }
 
+// getManagementApiEnvVars returns the env vars the operator sets on the container running
+// the management API
+func getManagementApiEnvVars() []corev1.EnvVar {
+	return []corev1.EnvVar{
+		{Name: "USE_MGMT_API", Value: "true"},
+		{Name: "MGMT_API_EXPLICIT_START", Value: "true"},
+		// TODO remove this post 1.0
+		{Name: "DSE_MGMT_EXPLICIT_START", Value: "true"},
+	}
+}
+
+// addManagementApiHandlers sets the probes and the preStop drain, which the management API
+// serves, on the container running it, unless they are already set
+func addManagementApiHandlers(dc *api.CassandraDatacenter, container *corev1.Container) error {
+	if container.LivenessProbe == nil {
+		container.LivenessProbe = probe(8080, "/api/v0/probes/liveness", 15, 15)
+	}
+
+	if container.ReadinessProbe == nil {
+		container.ReadinessProbe = probe(8080, "/api/v0/probes/readiness", 20, 10)
+	}
+
+	if container.Lifecycle == nil {
+		container.Lifecycle = &corev1.Lifecycle{}
+	}
+
+	if container.Lifecycle.PreStop == nil {
+		preStop, err := getPreStopHandler(dc)
+		if err != nil {
+			return err
+		}
+		container.Lifecycle.PreStop = preStop
+	}
+
+	return nil
+}
+
+// buildManagementApiSidecarContainer fills in the management API sidecar container of
+// ManagementApiModeSidecar, keeping the values already set on it from the PodTemplateSpec
+func buildManagementApiSidecarContainer(dc *api.CassandraDatacenter, container *corev1.Container) error {
+	container.Name = api.ManagementApiContainerName
+
+	if container.Image == "" {
+		container.Image = dc.Spec.ManagementApiImage
+	}
+
+	if container.ImagePullPolicy == "" && dc.Spec.ServerImagePullPolicy != "" {
+		container.ImagePullPolicy = dc.GetServerImagePullPolicy()
+	}
+
+	container.Env = combineEnvSlices(getManagementApiEnvVars(), container.Env)
+	container.Ports = combinePortSlices(
+		[]corev1.ContainerPort{{Name: api.ManagementApiPortName, ContainerPort: httphelper.ManagementApiPort}},
+		container.Ports)
+
+	return addManagementApiHandlers(dc, container)
+}
+
 // If values are provided in the matching containers in the
 // PodTemplateSpec field of the dc, they will override defaults.
-func buildContainers(dc *api.CassandraDatacenter, baseTemplate *corev1.PodTemplateSpec) error {
+func buildContainers(dc *api.CassandraDatacenter, rackName string, baseTemplate *corev1.PodTemplateSpec) error {
 
 	// Create new Container structs or get references to existing ones
 
 	cassContainer := &corev1.Container{}
 	loggerContainer := &corev1.Container{}
+	mgmtApiContainer := &corev1.Container{}
 
 	foundCass := false
 	foundLogger := false
+	foundMgmtApi := false
 	for i, c := range baseTemplate.Spec.Containers {
 		if c.Name == CassandraContainerName {
 			foundCass = true
@@ -397,9 +655,14 @@ func buildContainers(dc *api.CassandraDatacenter, baseTemplate *corev1.PodTempla
 		} else if c.Name == SystemLoggerContainerName {
 			foundLogger = true
 			loggerContainer = &baseTemplate.Spec.Containers[i]
+		} else if c.Name == api.ManagementApiContainerName {
+			foundMgmtApi = true
+			mgmtApiContainer = &baseTemplate.Spec.Containers[i]
 		}
 	}
 
+	sidecarMgmtApi := dc.GetManagementApiMode() == api.ManagementApiModeSidecar
+
 	// Cassandra container
 
 	cassContainer.Name = CassandraContainerName
@@ -412,50 +675,24 @@ func buildContainers(dc *api.CassandraDatacenter, baseTemplate *corev1.PodTempla
 		cassContainer.Image = serverImage
 	}
 
-	if reflect.DeepEqual(cassContainer.Resources, corev1.ResourceRequirements{}) {
-		cassContainer.Resources = dc.Spec.Resources
-	}
-
-	if cassContainer.LivenessProbe == nil {
-		cassContainer.LivenessProbe = probe(8080, "/api/v0/probes/liveness", 15, 15)
+	if cassContainer.ImagePullPolicy == "" && dc.Spec.ServerImagePullPolicy != "" {
+		cassContainer.ImagePullPolicy = dc.GetServerImagePullPolicy()
 	}
 
-	if cassContainer.ReadinessProbe == nil {
-		cassContainer.ReadinessProbe = probe(8080, "/api/v0/probes/readiness", 20, 10)
-	}
-
-	if cassContainer.Lifecycle == nil {
-		cassContainer.Lifecycle = &corev1.Lifecycle{}
+	if reflect.DeepEqual(cassContainer.Resources, corev1.ResourceRequirements{}) {
+		cassContainer.Resources = dc.GetRackResources(rackName)
 	}
 
-	if cassContainer.Lifecycle.PreStop == nil {
-		action, err := httphelper.GetMgmtApiWgetPostAction(dc, httphelper.WgetNodeDrainEndpoint, "")
-		if err != nil {
+	if !sidecarMgmtApi {
+		if err := addManagementApiHandlers(dc, cassContainer); err != nil {
 			return err
 		}
-		cassContainer.Lifecycle.PreStop = &corev1.Handler{
-			Exec: action,
-		}
 	}
 
 	// Combine env vars
 
-	envDefaults := []corev1.EnvVar{
-		{Name: "DS_LICENSE", Value: "accept"},
-		{Name: "DSE_AUTO_CONF_OFF", Value: "all"},
-		{Name: "USE_MGMT_API", Value: "true"},
-		{Name: "MGMT_API_EXPLICIT_START", Value: "true"},
-		// TODO remove this post 1.0
-		{Name: "DSE_MGMT_EXPLICIT_START", Value: "true"},
-	}
-
-	if dc.Spec.ServerType == "dse" && dc.Spec.DseWorkloads != nil {
-		envDefaults = append(
-			envDefaults,
-			corev1.EnvVar{Name: "JVM_EXTRA_OPTS", Value: getJvmExtraOpts(dc)})
-	}
-
-	cassContainer.Env = combineEnvSlices(envDefaults, cassContainer.Env)
+	cassContainer.Env = combineEnvSlices(getServerEnvVars(dc), cassContainer.Env)
+	cassContainer.EnvFrom = append(append([]corev1.EnvFromSource{}, dc.Spec.EnvFrom...), cassContainer.EnvFrom...)
 
 	// Combine ports
 
@@ -464,6 +701,17 @@ func buildContainers(dc *api.CassandraDatacenter, baseTemplate *corev1.PodTempla
 		return err
 	}
 
+	// The management API port belongs to the sidecar
+	if sidecarMgmtApi {
+		serverPorts := []corev1.ContainerPort{}
+		for _, port := range portDefaults {
+			if port.Name != api.ManagementApiPortName {
+				serverPorts = append(serverPorts, port)
+			}
+		}
+		portDefaults = serverPorts
+	}
+
 	cassContainer.Ports = combinePortSlices(portDefaults, cassContainer.Ports)
 
 	// Combine volumeMounts
@@ -471,13 +719,13 @@ func buildContainers(dc *api.CassandraDatacenter, baseTemplate *corev1.PodTempla
 	var volumeDefaults []corev1.VolumeMount
 	serverCfgMount := corev1.VolumeMount{
 		Name:      "server-config",
-		MountPath: "/config",
+		MountPath: dc.GetConfigMountPath(),
 	}
 	volumeDefaults = append(volumeDefaults, serverCfgMount)
 
 	cassServerLogsMount := corev1.VolumeMount{
 		Name:      "server-logs",
-		MountPath: "/var/log/cassandra",
+		MountPath: api.DefaultServerLogDirectory,
 	}
 
 	volumeMounts := combineVolumeMountSlices(volumeDefaults,
@@ -485,7 +733,7 @@ func buildContainers(dc *api.CassandraDatacenter, baseTemplate *corev1.PodTempla
 			cassServerLogsMount,
 			{
 				Name:      PvcName,
-				MountPath: "/var/lib/cassandra",
+				MountPath: dc.GetDataDirectory(),
 			},
 			{
 				Name:      "encryption-cred-storage",
@@ -516,6 +764,14 @@ func buildContainers(dc *api.CassandraDatacenter, baseTemplate *corev1.PodTempla
 
 	loggerContainer.Resources = *getResourcesOrDefault(&dc.Spec.SystemLoggerResources, &DefaultsLoggerContainer)
 
+	// Management API sidecar container
+
+	if sidecarMgmtApi {
+		if err := buildManagementApiSidecarContainer(dc, mgmtApiContainer); err != nil {
+			return err
+		}
+	}
+
 	// Note that append() can make copies of each element,
 	// so we call it after modifying any existing elements.
 
@@ -529,6 +785,10 @@ func buildContainers(dc *api.CassandraDatacenter, baseTemplate *corev1.PodTempla
 		}
 	}
 
+	if sidecarMgmtApi && !foundMgmtApi {
+		baseTemplate.Spec.Containers = append(baseTemplate.Spec.Containers, *mgmtApiContainer)
+	}
+
 	return nil
 }
 
@@ -593,38 +853,116 @@ func buildPodTemplateSpec(dc *api.CassandraDatacenter, nodeAffinityLabels map[st
 
 	podAnnotations := map[string]string{}
 
-	if baseTemplate.Annotations == nil {
-		baseTemplate.Annotations = make(map[string]string)
+	configHashKey, configHash, err := dc.GetConfigHashAnnotation()
+	if err != nil {
+		return nil, err
 	}
-	baseTemplate.Annotations = utils.MergeMap(baseTemplate.Annotations, podAnnotations)
+	podAnnotations[configHashKey] = configHash
+
+	mergePodAnnotations(dc, baseTemplate, podAnnotations)
 
 	// Affinity
 
-	affinity := &corev1.Affinity{}
-	affinity.NodeAffinity = calculateNodeAffinity(nodeAffinityLabels)
-	affinity.PodAntiAffinity = calculatePodAntiAffinity(dc.Spec.AllowMultipleNodesPerWorker)
-	baseTemplate.Spec.Affinity = affinity
+	baseTemplate.Spec.Affinity = calculateAffinity(dc, nodeAffinityLabels)
 
 	// Tolerations
 	baseTemplate.Spec.Tolerations = dc.Spec.Tolerations
 
+	// Readiness gates
+	baseTemplate.Spec.ReadinessGates = GetPodReadinessGates(dc)
+
 	// Volumes
 
 	addVolumes(dc, baseTemplate)
 
 	// Init Containers
 
-	err := buildInitContainers(dc, rackName, baseTemplate)
+	err = buildInitContainers(dc, rackName, baseTemplate)
 	if err != nil {
 		return nil, err
 	}
 
 	// Containers
 
-	err = buildContainers(dc, baseTemplate)
+	err = buildContainers(dc, rackName, baseTemplate)
 	if err != nil {
 		return nil, err
 	}
 
 	return baseTemplate, nil
 }
+
+// mergePodAnnotations merges the AdditionalPodAnnotations of the datacenter into the
+// annotations of the pod template, followed by the annotations the operator owns, which
+// take precedence
+func mergePodAnnotations(dc *api.CassandraDatacenter, template *corev1.PodTemplateSpec, operatorAnnotations map[string]string) {
+	if template.Annotations == nil {
+		template.Annotations = make(map[string]string)
+	}
+	template.Annotations = utils.MergeMap(template.Annotations, dc.Spec.AdditionalPodAnnotations, operatorAnnotations)
+}
+
+// PodTemplateInputs holds the effective values of a datacenter which feed the pod template
+// of a rack, to explain why the pods of a rack were rolled
+type PodTemplateInputs struct {
+	ServerImage        string
+	ConfigBuilderImage string
+	ConfigHash         string
+	Resources          corev1.ResourceRequirements
+	Env                []corev1.EnvVar
+	EnvFrom            []corev1.EnvFromSource
+	Affinity           *corev1.Affinity
+	Tolerations        []corev1.Toleration
+	PodAnnotations     map[string]string
+}
+
+// GetPodTemplateInputs returns the inputs of the pod template of the rack, resolved the
+// same way as when building the pod template
+func GetPodTemplateInputs(dc *api.CassandraDatacenter, rackName string) (*PodTemplateInputs, error) {
+	serverImage, err := makeImage(dc)
+	if err != nil {
+		return nil, err
+	}
+
+	configBuilderImage := dc.GetConfigBuilderImage()
+	if configBuilderImage == "" {
+		configBuilderImage = images.GetConfigBuilderImage()
+	}
+
+	configHash, err := dc.GetConfigHash()
+	if err != nil {
+		return nil, err
+	}
+
+	nodeAffinityLabels, err := rackNodeAffinitylabels(dc, rackName)
+	if err != nil {
+		return nil, err
+	}
+
+	return &PodTemplateInputs{
+		ServerImage:        serverImage,
+		ConfigBuilderImage: configBuilderImage,
+		ConfigHash:         configHash,
+		Resources:          dc.GetRackResources(rackName),
+		Env:                getServerEnvVars(dc),
+		EnvFrom:            dc.Spec.EnvFrom,
+		Affinity:           calculateAffinity(dc, nodeAffinityLabels),
+		Tolerations:        dc.Spec.Tolerations,
+		PodAnnotations:     dc.Spec.AdditionalPodAnnotations,
+	}, nil
+}
+
+// DiffPodTemplateInputs returns the names of the fields which differ between the inputs,
+// in the order they are declared
+func DiffPodTemplateInputs(oldInputs *PodTemplateInputs, newInputs *PodTemplateInputs) []string {
+	oldValue := reflect.ValueOf(*oldInputs)
+	newValue := reflect.ValueOf(*newInputs)
+
+	changed := []string{}
+	for i := 0; i < oldValue.NumField(); i++ {
+		if !reflect.DeepEqual(oldValue.Field(i).Interface(), newValue.Field(i).Interface()) {
+			changed = append(changed, oldValue.Type().Field(i).Name)
+		}
+	}
+	return changed
+}
@@ -4,9 +4,12 @@
 package reconciliation
 
 import (
+	"encoding/json"
 	"fmt"
+	"io/ioutil"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"os"
+	"os/exec"
 	"reflect"
 	"testing"
 
@@ -21,19 +24,82 @@ import (
 
 func Test_calculatePodAntiAffinity(t *testing.T) {
 	t.Run("check when we allow more than one server pod per node", func(t *testing.T) {
-		paa := calculatePodAntiAffinity(true)
+		paa := calculatePodAntiAffinity(true, corev1.LabelHostname, nil)
 		if paa != nil {
 			t.Errorf("calculatePodAntiAffinity() = %v, and we want nil", paa)
 		}
 	})
 
 	t.Run("check when we do not allow more than one server pod per node", func(t *testing.T) {
-		paa := calculatePodAntiAffinity(false)
+		paa := calculatePodAntiAffinity(false, corev1.LabelHostname, nil)
 		if paa == nil ||
 			len(paa.RequiredDuringSchedulingIgnoredDuringExecution) != 1 {
 			t.Errorf("calculatePodAntiAffinity() = %v, and we want one element in RequiredDuringSchedulingIgnoredDuringExecution", paa)
 		}
 	})
+
+	t.Run("check the topology key", func(t *testing.T) {
+		paa := calculatePodAntiAffinity(false, zoneLabel, nil)
+		assert.Equal(t, zoneLabel, paa.RequiredDuringSchedulingIgnoredDuringExecution[0].TopologyKey)
+	})
+}
+
+func Test_calculateAffinity(t *testing.T) {
+	dc := &api.CassandraDatacenter{}
+
+	t.Run("check that the anti-affinity is required by default", func(t *testing.T) {
+		affinity := calculateAffinity(dc, map[string]string{zoneLabel: "thezone"})
+		assert.NotNil(t, affinity.NodeAffinity)
+		assert.Len(t, affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution, 1)
+		assert.Empty(t, affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution)
+	})
+
+	t.Run("check that relaxed scheduling makes the anti-affinity preferred", func(t *testing.T) {
+		dc.Spec.RelaxedScheduling = true
+		affinity := calculateAffinity(dc, map[string]string{zoneLabel: "thezone"})
+		assert.NotNil(t, affinity.NodeAffinity)
+		assert.Empty(t, affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution)
+		preferred := affinity.PodAntiAffinity.PreferredDuringSchedulingIgnoredDuringExecution
+		assert.Len(t, preferred, 1)
+		assert.Equal(t, "kubernetes.io/hostname", preferred[0].PodAffinityTerm.TopologyKey)
+	})
+
+	t.Run("check that the anti-affinity selects the pods of the scope", func(t *testing.T) {
+		dc := &api.CassandraDatacenter{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "dc1",
+			},
+			Spec: api.CassandraDatacenterSpec{
+				ClusterName: "cluster1",
+			},
+		}
+		affinity := calculateAffinity(dc, nil)
+		selector := affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].LabelSelector
+		assert.Equal(t, &metav1.LabelSelector{
+			MatchExpressions: []metav1.LabelSelectorRequirement{
+				{Key: api.ClusterLabel, Operator: metav1.LabelSelectorOpExists},
+				{Key: api.DatacenterLabel, Operator: metav1.LabelSelectorOpExists},
+				{Key: api.RackLabel, Operator: metav1.LabelSelectorOpExists},
+			},
+		}, selector, "an unset scope keeps the selector of all server pods")
+
+		dc.Spec.AntiAffinityScope = api.AntiAffinityScopeDatacenter
+		affinity = calculateAffinity(dc, nil)
+		selector = affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].LabelSelector
+		assert.Equal(t, map[string]string{api.ClusterLabel: "cluster1", api.DatacenterLabel: "dc1"}, selector.MatchLabels)
+		assert.Equal(t, []metav1.LabelSelectorRequirement{{Key: api.RackLabel, Operator: metav1.LabelSelectorOpExists}}, selector.MatchExpressions)
+
+		dc.Spec.AntiAffinityScope = api.AntiAffinityScopeCluster
+		affinity = calculateAffinity(dc, nil)
+		selector = affinity.PodAntiAffinity.RequiredDuringSchedulingIgnoredDuringExecution[0].LabelSelector
+		assert.Equal(t, map[string]string{api.ClusterLabel: "cluster1"}, selector.MatchLabels)
+	})
+
+	t.Run("check that relaxed scheduling keeps no anti-affinity with multiple nodes per worker", func(t *testing.T) {
+		dc.Spec.AllowMultipleNodesPerWorker = true
+		affinity := calculateAffinity(dc, nil)
+		assert.Nil(t, affinity.PodAntiAffinity)
+	})
 }
 
 func Test_calculateNodeAffinity(t *testing.T) {
@@ -168,7 +234,7 @@ func TestCassandraDatacenter_buildContainers_systemlogger_resources_set(t *testi
 	}
 
 	podTemplateSpec := &corev1.PodTemplateSpec{}
-	err := buildContainers(dc, podTemplateSpec)
+	err := buildContainers(dc, "default", podTemplateSpec)
 	containers := podTemplateSpec.Spec.Containers
 	assert.NotNil(t, containers, "Unexpected containers containers received")
 	assert.Nil(t, err, "Unexpected error encountered")
@@ -188,7 +254,7 @@ func TestCassandraDatacenter_buildContainers_systemlogger_resources_set_when_not
 	}
 
 	podTemplateSpec := &corev1.PodTemplateSpec{}
-	err := buildContainers(dc, podTemplateSpec)
+	err := buildContainers(dc, "default", podTemplateSpec)
 	containers := podTemplateSpec.Spec.Containers
 	assert.NotNil(t, containers, "Unexpected containers containers received")
 	assert.Nil(t, err, "Unexpected error encountered")
@@ -221,7 +287,7 @@ func TestCassandraDatacenter_buildContainers_use_cassandra_settings(t *testing.T
 	podTemplateSpec := &corev1.PodTemplateSpec{}
 	podTemplateSpec.Spec.Containers = append(podTemplateSpec.Spec.Containers, cassContainer)
 
-	err := buildContainers(dc, podTemplateSpec)
+	err := buildContainers(dc, "default", podTemplateSpec)
 	containers := podTemplateSpec.Spec.Containers
 	assert.NotNil(t, containers, "Unexpected containers containers received")
 	assert.Nil(t, err, "Unexpected error encountered")
@@ -233,6 +299,60 @@ func TestCassandraDatacenter_buildContainers_use_cassandra_settings(t *testing.T
 	}
 }
 
+func TestCassandraDatacenter_buildContainers_DataDirectory(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "bob",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			StorageConfig: api.StorageConfig{
+				DataDirectory: "/data/cassandra",
+			},
+		},
+	}
+
+	podTemplateSpec := &corev1.PodTemplateSpec{}
+	err := buildContainers(dc, "default", podTemplateSpec)
+	assert.NoError(t, err)
+
+	assert.Contains(t, podTemplateSpec.Spec.Containers[0].VolumeMounts, corev1.VolumeMount{
+		Name:      PvcName,
+		MountPath: "/data/cassandra",
+	})
+}
+
+func TestCassandraDatacenter_buildContainers_rack_resources(t *testing.T) {
+	rackResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU: resource.MustParse("4"),
+		},
+	}
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "bob",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU: resource.MustParse("1"),
+				},
+			},
+			Racks: []api.Rack{
+				{Name: "r1", Resources: &rackResources},
+				{Name: "r2"},
+			},
+		},
+	}
+
+	podTemplateSpec := &corev1.PodTemplateSpec{}
+	assert.NoError(t, buildContainers(dc, "r1", podTemplateSpec))
+	assert.Equal(t, rackResources, podTemplateSpec.Spec.Containers[0].Resources)
+
+	podTemplateSpec = &corev1.PodTemplateSpec{}
+	assert.NoError(t, buildContainers(dc, "r2", podTemplateSpec))
+	assert.Equal(t, dc.Spec.Resources, podTemplateSpec.Spec.Containers[0].Resources)
+}
+
 func TestServerConfigInitContainerEnvVars(t *testing.T) {
 	rack := "rack1"
 	podIPEnvVar := corev1.EnvVar{Name: "POD_IP", ValueFrom: selectorFromFieldPath("status.podIP")}
@@ -342,6 +462,117 @@ func TestServerConfigInitContainerEnvVars(t *testing.T) {
 	}
 }
 
+func Test_getConfigBuilderEnvVars(t *testing.T) {
+	tests := []struct {
+		serverType    string
+		serverVersion string
+	}{
+		{serverType: "cassandra", serverVersion: "3.11.10"},
+		{serverType: "dse", serverVersion: "6.8.4"},
+	}
+
+	for _, tt := range tests {
+		dc := &api.CassandraDatacenter{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "dc1",
+			},
+			Spec: api.CassandraDatacenterSpec{
+				ClusterName:   "cluster1",
+				ServerType:    tt.serverType,
+				ServerVersion: tt.serverVersion,
+			},
+		}
+
+		envVars, err := getConfigBuilderEnvVars(dc, "rack1")
+		assert.NoError(t, err)
+
+		assert.Contains(t, envVars, corev1.EnvVar{Name: "PRODUCT_NAME", Value: tt.serverType})
+		assert.Contains(t, envVars, corev1.EnvVar{Name: "PRODUCT_VERSION", Value: tt.serverVersion})
+		assert.Contains(t, envVars, corev1.EnvVar{Name: "RACK_NAME", Value: "rack1"})
+		assert.Equal(t, "CONFIG_FILE_DATA", envVars[len(envVars)-1].Name)
+	}
+}
+
+func Test_buildInitContainers_listenInterface(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "cluster1",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			Config:        []byte(`{"cassandra-yaml":{"num_tokens":16}}`),
+		},
+	}
+
+	podTemplateSpec := &corev1.PodTemplateSpec{}
+	assert.NoError(t, buildInitContainers(dc, "rack1", podTemplateSpec))
+	assert.Len(t, podTemplateSpec.Spec.InitContainers, 1)
+
+	dc.Spec.Networking = &api.NetworkingConfig{
+		HostNetwork:     true,
+		ListenInterface: "bond0",
+	}
+	podTemplateSpec = &corev1.PodTemplateSpec{}
+	assert.NoError(t, buildInitContainers(dc, "rack1", podTemplateSpec))
+	initContainers := podTemplateSpec.Spec.InitContainers
+	assert.Len(t, initContainers, 2)
+
+	// The broadcast addresses are still rendered from the pod address
+	serverCfg := initContainers[0]
+	assert.Equal(t, ServerConfigContainerName, serverCfg.Name)
+	assert.Equal(t, "POD_IP", serverCfg.Env[0].Name)
+	configData := serverCfg.Env[len(serverCfg.Env)-1]
+	assert.Equal(t, "CONFIG_FILE_DATA", configData.Name)
+	var config map[string]map[string]interface{}
+	assert.NoError(t, json.Unmarshal([]byte(configData.Value), &config))
+	assert.Equal(t, "bond0", config["cassandra-yaml"]["listen_interface"])
+	assert.NotContains(t, config["cassandra-yaml"], "listen_address")
+
+	listenInterfaceCfg := initContainers[1]
+	assert.Equal(t, ListenInterfaceConfigContainerName, listenInterfaceCfg.Name)
+	serverImage, err := makeImage(dc)
+	assert.NoError(t, err)
+	assert.Equal(t, serverImage, listenInterfaceCfg.Image)
+	assert.Equal(t, getListenInterfaceConfigCommand("/config"), listenInterfaceCfg.Command)
+	assert.Equal(t, []corev1.VolumeMount{{Name: "server-config", MountPath: "/config"}}, listenInterfaceCfg.VolumeMounts)
+}
+
+func Test_getListenInterfaceConfigCommand(t *testing.T) {
+	configPath, err := ioutil.TempDir("", "config")
+	assert.NoError(t, err)
+	defer os.RemoveAll(configPath)
+
+	command := getListenInterfaceConfigCommand(configPath)
+	if _, err := exec.LookPath(command[0]); err != nil {
+		t.Skipf("%s is not available", command[0])
+	}
+
+	// cassandra.yaml as the config builder renders it for a listen interface
+	rendered := `cluster_name: cluster1
+listen_address: 10.0.0.1
+listen_interface: bond0
+broadcast_address: 10.0.0.1
+rpc_address: 0.0.0.0
+broadcast_rpc_address: 10.0.0.1
+`
+	configFile := command[len(command)-1]
+	assert.NoError(t, ioutil.WriteFile(configFile, []byte(rendered), 0644))
+
+	output, err := exec.Command(command[0], command[1:]...).CombinedOutput()
+	assert.NoError(t, err, string(output))
+
+	cassandraYaml, err := ioutil.ReadFile(configFile)
+	assert.NoError(t, err)
+	assert.Equal(t, `cluster_name: cluster1
+listen_interface: bond0
+broadcast_address: 10.0.0.1
+rpc_address: 0.0.0.0
+broadcast_rpc_address: 10.0.0.1
+`, string(cassandraYaml))
+}
+
 func TestCassandraDatacenter_buildContainers_override_other_containers(t *testing.T) {
 	dc := &api.CassandraDatacenter{
 		Spec: api.CassandraDatacenterSpec{
@@ -367,7 +598,7 @@ func TestCassandraDatacenter_buildContainers_override_other_containers(t *testin
 		},
 	}
 
-	err := buildContainers(dc, podTemplateSpec)
+	err := buildContainers(dc, "default", podTemplateSpec)
 	containers := podTemplateSpec.Spec.Containers
 	assert.NotNil(t, containers, "Unexpected containers containers received")
 	assert.Nil(t, err, "Unexpected error encountered")
@@ -779,6 +1010,83 @@ func TestCassandraDatacenter_buildPodTemplateSpec_labels_merge(t *testing.T) {
 	}
 }
 
+func Test_combineReadinessGateSlices(t *testing.T) {
+	operatorGates := []corev1.PodReadinessGate{
+		{ConditionType: "cassandra.datastax.com/management-api"},
+	}
+	userGates := []corev1.PodReadinessGate{
+		{ConditionType: "example.com/load-balancer"},
+		{ConditionType: "cassandra.datastax.com/management-api"},
+		{ConditionType: "example.com/load-balancer"},
+	}
+
+	assert.Equal(t, []corev1.PodReadinessGate{
+		{ConditionType: "cassandra.datastax.com/management-api"},
+		{ConditionType: "example.com/load-balancer"},
+	}, combineReadinessGateSlices(operatorGates, userGates))
+	assert.Nil(t, combineReadinessGateSlices(nil, nil))
+}
+
+func TestCassandraDatacenter_buildPodTemplateSpec_readinessGates(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "bob",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			PodTemplateSpec: &corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					ReadinessGates: []corev1.PodReadinessGate{
+						{ConditionType: "example.com/load-balancer"},
+						{ConditionType: "example.com/load-balancer"},
+					},
+				},
+			},
+		},
+	}
+
+	spec, err := buildPodTemplateSpec(dc, nil, "testrack")
+	assert.NoError(t, err)
+	assert.Equal(t, []corev1.PodReadinessGate{{ConditionType: "example.com/load-balancer"}}, spec.Spec.ReadinessGates)
+}
+
+func TestCassandraDatacenter_buildPodTemplateSpec_config_hash_annotation(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "bob",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			Config:        []byte(`{"cassandra-yaml":{"num_tokens":8}}`),
+		},
+	}
+
+	spec, err := buildPodTemplateSpec(dc, nil, "testrack")
+	assert.NoError(t, err)
+	configHash, err := dc.GetConfigHash()
+	assert.NoError(t, err)
+	assert.Equal(t, configHash, spec.Annotations[api.ConfigHashAnnotation])
+}
+
+func TestCassandraDatacenter_buildPodTemplateSpec_additional_pod_annotations(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "bob",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			AdditionalPodAnnotations: map[string]string{
+				"sidecar.istio.io/inject": "false",
+				api.ConfigHashAnnotation:  "user-hash",
+			},
+		},
+	}
+
+	spec, err := buildPodTemplateSpec(dc, nil, "testrack")
+	assert.NoError(t, err)
+	configHash, err := dc.GetConfigHash()
+	assert.NoError(t, err)
+	assert.Equal(t, "false", spec.Annotations["sidecar.istio.io/inject"])
+	assert.Equal(t, configHash, spec.Annotations[api.ConfigHashAnnotation])
+}
+
 func TestCassandraDatacenter_buildPodTemplateSpec_overrideSecurityContext(t *testing.T) {
 	uid := int64(1111)
 	gid := int64(2222)
@@ -893,7 +1201,7 @@ func TestCassandraDatacenter_buildContainers_DisableSystemLoggerSidecar(t *testi
 
 	podTemplateSpec := &corev1.PodTemplateSpec{}
 
-	err := buildContainers(dc, podTemplateSpec)
+	err := buildContainers(dc, "default", podTemplateSpec)
 
 	assert.NoError(t, err, "should not have gotten error from calling buildContainers()")
 
@@ -915,7 +1223,7 @@ func TestCassandraDatacenter_buildContainers_EnableSystemLoggerSidecar_CustomIma
 
 	podTemplateSpec := &corev1.PodTemplateSpec{}
 
-	err := buildContainers(dc, podTemplateSpec)
+	err := buildContainers(dc, "default", podTemplateSpec)
 
 	assert.NoError(t, err, "should not have gotten error from calling buildContainers()")
 
@@ -1194,3 +1502,171 @@ func TestTolerations(t *testing.T) {
 	// using ElementsMatch instead of Equal because we do not really care about ordering.
 	assert.ElementsMatch(t, tolerations, spec.Spec.Tolerations, "tolerations do not match")
 }
+
+func TestCassandraDatacenter_buildContainers_envFrom(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "bob",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			EnvFrom: []corev1.EnvFromSource{
+				{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "jvm-tuning"}}},
+			},
+		},
+	}
+
+	podTemplateSpec := &corev1.PodTemplateSpec{}
+	err := buildContainers(dc, "default", podTemplateSpec)
+	assert.NoError(t, err)
+
+	cassContainer := podTemplateSpec.Spec.Containers[0]
+	assert.Equal(t, CassandraContainerName, cassContainer.Name)
+	assert.Equal(t, dc.Spec.EnvFrom, cassContainer.EnvFrom)
+}
+
+func TestCassandraDatacenter_buildContainers_configMountPath(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:     "bob",
+			ServerType:      "cassandra",
+			ServerVersion:   "3.11.7",
+			ConfigMountPath: "/etc/cassandra-config/",
+		},
+	}
+
+	podTemplateSpec := &corev1.PodTemplateSpec{}
+	assert.NoError(t, buildInitContainers(dc, "default", podTemplateSpec))
+	assert.NoError(t, buildContainers(dc, "default", podTemplateSpec))
+
+	initMounts := podTemplateSpec.Spec.InitContainers[0].VolumeMounts
+	assert.Equal(t, []corev1.VolumeMount{{Name: "server-config", MountPath: "/etc/cassandra-config"}}, initMounts)
+
+	found := false
+	for _, mount := range podTemplateSpec.Spec.Containers[0].VolumeMounts {
+		if mount.Name == "server-config" {
+			found = true
+			assert.Equal(t, "/etc/cassandra-config", mount.MountPath)
+		}
+	}
+	assert.True(t, found, "cassandra container should mount the server config")
+}
+
+func TestCassandraDatacenter_buildContainers_preStop(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "bob",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+		},
+	}
+
+	podTemplateSpec := &corev1.PodTemplateSpec{}
+	assert.NoError(t, buildContainers(dc, "default", podTemplateSpec))
+	preStop := podTemplateSpec.Spec.Containers[0].Lifecycle.PreStop
+	assert.NotNil(t, preStop)
+	assert.Contains(t, preStop.Exec.Command, "http://localhost:8080/api/v0/ops/node/drain")
+
+	dc.Spec.DisablePreStopDrain = true
+	podTemplateSpec = &corev1.PodTemplateSpec{}
+	assert.NoError(t, buildContainers(dc, "default", podTemplateSpec))
+	assert.Nil(t, podTemplateSpec.Spec.Containers[0].Lifecycle.PreStop)
+}
+
+func TestCassandraDatacenter_buildContainers_managementApiSidecar(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:        "bob",
+			ServerType:         "cassandra",
+			ServerVersion:      "3.11.7",
+			ManagementApiMode:  api.ManagementApiModeSidecar,
+			ManagementApiImage: "example/management-api:1.0",
+		},
+	}
+
+	podTemplateSpec := &corev1.PodTemplateSpec{
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{{
+				Name: api.ManagementApiContainerName,
+				Env:  []corev1.EnvVar{{Name: "MGMT_API_SOCKET", Value: "/shared/cassandra.sock"}},
+			}},
+		},
+	}
+	assert.NoError(t, buildContainers(dc, "default", podTemplateSpec))
+
+	containers := map[string]corev1.Container{}
+	for _, container := range podTemplateSpec.Spec.Containers {
+		containers[container.Name] = container
+	}
+
+	cassContainer := containers[CassandraContainerName]
+	assert.Nil(t, cassContainer.LivenessProbe)
+	assert.Nil(t, cassContainer.ReadinessProbe)
+	assert.Nil(t, cassContainer.Lifecycle)
+	for _, port := range cassContainer.Ports {
+		assert.NotEqual(t, api.ManagementApiPortName, port.Name)
+	}
+	for _, envVar := range cassContainer.Env {
+		assert.NotEqual(t, "USE_MGMT_API", envVar.Name)
+	}
+
+	mgmtApiContainer := containers[api.ManagementApiContainerName]
+	assert.Equal(t, "example/management-api:1.0", mgmtApiContainer.Image)
+	assert.Equal(t, []corev1.ContainerPort{{Name: api.ManagementApiPortName, ContainerPort: 8080}}, mgmtApiContainer.Ports)
+	assert.Equal(t, "/api/v0/probes/liveness", mgmtApiContainer.LivenessProbe.HTTPGet.Path)
+	assert.Equal(t, "/api/v0/probes/readiness", mgmtApiContainer.ReadinessProbe.HTTPGet.Path)
+	assert.Contains(t, mgmtApiContainer.Lifecycle.PreStop.Exec.Command, "http://localhost:8080/api/v0/ops/node/drain")
+	assert.Contains(t, mgmtApiContainer.Env, corev1.EnvVar{Name: "MGMT_API_SOCKET", Value: "/shared/cassandra.sock"})
+	assert.Contains(t, mgmtApiContainer.Env, corev1.EnvVar{Name: "USE_MGMT_API", Value: "true"})
+}
+
+func TestCassandraDatacenter_buildContainers_pullPolicies(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "bob",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+		},
+	}
+
+	podTemplateSpec := &corev1.PodTemplateSpec{}
+	assert.NoError(t, buildInitContainers(dc, "default", podTemplateSpec))
+	assert.NoError(t, buildContainers(dc, "default", podTemplateSpec))
+	assert.Empty(t, podTemplateSpec.Spec.InitContainers[0].ImagePullPolicy)
+	assert.Empty(t, podTemplateSpec.Spec.Containers[0].ImagePullPolicy)
+
+	dc.Spec.ServerImagePullPolicy = corev1.PullIfNotPresent
+	dc.Spec.ConfigBuilderImagePullPolicy = corev1.PullAlways
+	podTemplateSpec = &corev1.PodTemplateSpec{}
+	assert.NoError(t, buildInitContainers(dc, "default", podTemplateSpec))
+	assert.NoError(t, buildContainers(dc, "default", podTemplateSpec))
+	assert.Equal(t, corev1.PullAlways, podTemplateSpec.Spec.InitContainers[0].ImagePullPolicy)
+	assert.Equal(t, corev1.PullIfNotPresent, podTemplateSpec.Spec.Containers[0].ImagePullPolicy)
+}
+
+func TestDiffPodTemplateInputs(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "bob",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			Racks:         []api.Rack{{Name: "r1", Zone: "zone-a"}},
+		},
+	}
+
+	oldInputs, err := GetPodTemplateInputs(dc, "r1")
+	assert.NoError(t, err)
+	assert.NotEmpty(t, oldInputs.ServerImage)
+	assert.NotEmpty(t, oldInputs.ConfigHash)
+	assert.NotNil(t, oldInputs.Affinity.NodeAffinity)
+
+	newDc := dc.DeepCopy()
+	newDc.Spec.Config = []byte(`{"cassandra-yaml":{"num_tokens":16}}`)
+	newDc.Spec.Resources = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{corev1.ResourceMemory: resource.MustParse("4Gi")},
+	}
+	newInputs, err := GetPodTemplateInputs(newDc, "r1")
+	assert.NoError(t, err)
+
+	assert.Empty(t, DiffPodTemplateInputs(oldInputs, oldInputs))
+	assert.Equal(t, []string{"ConfigHash", "Resources"}, DiffPodTemplateInputs(oldInputs, newInputs))
+}
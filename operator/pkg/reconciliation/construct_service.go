@@ -8,6 +8,7 @@ import (
 	"net"
 
 	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/operator/pkg/httphelper"
 	"github.com/k8ssandra/cass-operator/operator/pkg/oplabels"
 	"github.com/k8ssandra/cass-operator/operator/pkg/utils"
 
@@ -26,12 +27,13 @@ func newServiceForCassandraDatacenter(dc *api.CassandraDatacenter) *corev1.Servi
 	if dc.IsNodePortEnabled() {
 		nativePort = dc.GetNodePortNativePort()
 	}
+	prometheusPort := dc.GetPrometheusPort()
 
 	ports := []corev1.ServicePort{
 		namedServicePort("native", nativePort, nativePort),
 		namedServicePort("tls-native", 9142, 9142),
 		namedServicePort("mgmt-api", 8080, 8080),
-		namedServicePort("prometheus", 9103, 9103),
+		namedServicePort("prometheus", prometheusPort, prometheusPort),
 		namedServicePort("thrift", 9160, 9160),
 	}
 
@@ -97,28 +99,14 @@ func namedServicePort(name string, port int, targetPort int) corev1.ServicePort
 }
 
 func buildLabelSelectorForSeedService(dc *api.CassandraDatacenter) map[string]string {
-	labels := dc.GetClusterLabels()
-
 	// narrow selection to just the seed nodes
-	labels[api.SeedNodeLabel] = "true"
-
-	return labels
+	return dc.GetSeedNodeLabels()
 }
 
 // newSeedServiceForCassandraDatacenter creates a headless service owned by the CassandraDatacenter which will attach to all seed
 // nodes in the cluster
-func newSeedServiceForCassandraDatacenter(dc *api.CassandraDatacenter) *corev1.Service {
-	service := makeGenericHeadlessService(dc)
-	service.ObjectMeta.Name = dc.GetSeedServiceName()
-
-	labels := dc.GetClusterLabels()
-	oplabels.AddManagedByLabel(labels)
-	service.ObjectMeta.Labels = labels
-
-	service.Spec.Selector = buildLabelSelectorForSeedService(dc)
-	service.Spec.PublishNotReadyAddresses = true
-
-	addAdditionalOptions(service, &dc.Spec.AdditionalServiceConfig.SeedService)
+func newSeedServiceForCassandraDatacenter(dc *api.CassandraDatacenter, publishNotReadyAddresses bool) *corev1.Service {
+	service := dc.NewSeedService(publishNotReadyAddresses)
 
 	utils.AddHashAnnotation(service)
 
@@ -235,18 +223,41 @@ func newNodePortServiceForCassandraDatacenter(dc *api.CassandraDatacenter) *core
 	return service
 }
 
+// newManagementApiServiceForCassandraDatacenter creates a service owned by the
+// CassandraDatacenter, which load balances over the management API of the ready server pods
+func newManagementApiServiceForCassandraDatacenter(dc *api.CassandraDatacenter) (*corev1.Service, error) {
+	protocol, err := httphelper.GetManagementApiProtocol(dc)
+	if err != nil {
+		return nil, err
+	}
+
+	service := makeGenericHeadlessService(dc)
+	service.ObjectMeta.Name = dc.GetManagementApiServiceName()
+	service.Spec.ClusterIP = ""
+
+	// The management API serves TLS on the same port
+	service.Spec.Ports = []corev1.ServicePort{
+		namedServicePort("mgmt-api-"+protocol, httphelper.ManagementApiPort, httphelper.ManagementApiPort),
+	}
+
+	utils.AddHashAnnotation(service)
+
+	return service, nil
+}
+
 // newAllPodsServiceForCassandraDatacenter creates a headless service owned by the CassandraDatacenter,
 // which covers all server pods in the datacenter, whether they are ready or not
 func newAllPodsServiceForCassandraDatacenter(dc *api.CassandraDatacenter) *corev1.Service {
 	service := makeGenericHeadlessService(dc)
 	service.ObjectMeta.Name = dc.GetAllPodsServiceName()
 	service.ObjectMeta.Labels[api.PromMetricsLabel] = "true"
-	service.Spec.PublishNotReadyAddresses = true
+	service.Spec.PublishNotReadyAddresses = dc.GetAllPodsServicePublishNotReadyAddresses()
 
 	nativePort := api.DefaultNativePort
 	if dc.IsNodePortEnabled() {
 		nativePort = dc.GetNodePortNativePort()
 	}
+	prometheusPort := dc.GetPrometheusPort()
 
 	service.Spec.Ports = []corev1.ServicePort{
 		{
@@ -256,7 +267,7 @@ func newAllPodsServiceForCassandraDatacenter(dc *api.CassandraDatacenter) *corev
 			Name: "mgmt-api", Port: 8080, TargetPort: intstr.FromInt(8080),
 		},
 		{
-			Name: "prometheus", Port: 9103, TargetPort: intstr.FromInt(9103),
+			Name: "prometheus", Port: int32(prometheusPort), TargetPort: intstr.FromInt(prometheusPort),
 		},
 	}
 
@@ -53,3 +53,71 @@ func TestCassandraDatacenter_allPodsServiceLabels(t *testing.T) {
 		t.Errorf("allPodsService labels = %v, want %v", gotLabels, wantLabels)
 	}
 }
+
+func TestCassandraDatacenter_allPodsServicePublishNotReadyAddresses(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "dc1",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName: "bob",
+		},
+	}
+
+	service := newAllPodsServiceForCassandraDatacenter(dc)
+	if !service.Spec.PublishNotReadyAddresses {
+		t.Errorf("allPodsService publishNotReadyAddresses = false, want true by default")
+	}
+
+	publish := false
+	dc.Spec.AllPodsServicePublishNotReadyAddresses = &publish
+
+	service = newAllPodsServiceForCassandraDatacenter(dc)
+	if service.Spec.PublishNotReadyAddresses {
+		t.Errorf("allPodsService publishNotReadyAddresses = true, want false when disabled")
+	}
+}
+
+func TestCassandraDatacenter_managementApiService(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc1",
+			Namespace: "test",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName: "bob",
+			ManagementApiAuth: api.ManagementApiAuthConfig{
+				Insecure: &api.ManagementApiAuthInsecureConfig{},
+			},
+		},
+	}
+
+	service, err := newManagementApiServiceForCassandraDatacenter(dc)
+	if err != nil {
+		t.Fatalf("newManagementApiServiceForCassandraDatacenter() err = %v", err)
+	}
+	if service.Name != "bob-dc1-mgmt-api-service" || service.Spec.ClusterIP != "" {
+		t.Errorf("mgmtApiService name = %s, clusterIP = %q, want a ClusterIP service named bob-dc1-mgmt-api-service",
+			service.Name, service.Spec.ClusterIP)
+	}
+	if !reflect.DeepEqual(dc.GetDatacenterLabels(), service.Spec.Selector) {
+		t.Errorf("mgmtApiService selector = %v, want %v", service.Spec.Selector, dc.GetDatacenterLabels())
+	}
+	if len(service.Spec.Ports) != 1 || service.Spec.Ports[0].Name != "mgmt-api-http" || service.Spec.Ports[0].Port != 8080 {
+		t.Errorf("mgmtApiService ports = %v, want mgmt-api-http on 8080", service.Spec.Ports)
+	}
+
+	dc.Spec.ManagementApiAuth = api.ManagementApiAuthConfig{
+		Manual: &api.ManagementApiAuthManualConfig{
+			ClientSecretName: "client-secret",
+			ServerSecretName: "server-secret",
+		},
+	}
+	service, err = newManagementApiServiceForCassandraDatacenter(dc)
+	if err != nil {
+		t.Fatalf("newManagementApiServiceForCassandraDatacenter() err = %v", err)
+	}
+	if service.Spec.Ports[0].Name != "mgmt-api-https" || service.Spec.Ports[0].Port != 8080 {
+		t.Errorf("mgmtApiService ports = %v, want mgmt-api-https on 8080", service.Spec.Ports)
+	}
+}
@@ -7,6 +7,7 @@ package reconciliation
 
 import (
 	"fmt"
+	"strings"
 
 	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
 	"github.com/k8ssandra/cass-operator/operator/pkg/httphelper"
@@ -41,7 +42,7 @@ func newNamespacedNameForStatefulSet(
 	dc *api.CassandraDatacenter,
 	rackName string) types.NamespacedName {
 
-	name := dc.Spec.ClusterName + "-" + dc.Name + "-" + rackName + "-sts"
+	name := dc.GetStatefulSetName(rackName)
 	ns := dc.Namespace
 
 	return types.NamespacedName{
@@ -88,6 +89,65 @@ func rackNodeAffinitylabels(dc *api.CassandraDatacenter, rackName string) (map[s
 	return nodeAffinityLabels, nil
 }
 
+// newVolumeClaimTemplates returns the volume claim templates of a rack's statefulset: the
+// server data volume followed by the additional volumes from the StorageConfig
+func newVolumeClaimTemplates(dc *api.CassandraDatacenter, pvcLabels map[string]string) ([]corev1.PersistentVolumeClaim, error) {
+	if dc.Spec.StorageConfig.CassandraDataVolumeClaimSpec == nil {
+		err := fmt.Errorf("StorageConfig.cassandraDataVolumeClaimSpec is required")
+		return nil, err
+	}
+
+	volumeClaimTemplates := []corev1.PersistentVolumeClaim{{
+		ObjectMeta: metav1.ObjectMeta{
+			Labels: pvcLabels,
+			Name:   PvcName,
+		},
+		Spec: *dc.Spec.StorageConfig.CassandraDataVolumeClaimSpec,
+	}}
+
+	for _, storage := range dc.Spec.StorageConfig.AdditionalVolumes {
+		pvc := corev1.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   storage.Name,
+				Labels: pvcLabels,
+			},
+			Spec: storage.PVCSpec,
+		}
+
+		volumeClaimTemplates = append(volumeClaimTemplates, pvc)
+	}
+
+	return volumeClaimTemplates, nil
+}
+
+// GetExpectedPersistentVolumeClaims returns the PersistentVolumeClaims the statefulsets of
+// the datacenter create for its current size, named <template>-<pod name> like the
+// statefulset controller names them, ordered by rack, pod and volume claim template.
+func GetExpectedPersistentVolumeClaims(dc *api.CassandraDatacenter) ([]corev1.PersistentVolumeClaim, error) {
+	pvcs := []corev1.PersistentVolumeClaim{}
+	for _, sts := range dc.GetExpectedStatefulSets() {
+		pvcLabels := dc.GetRackLabels(sts.RackName)
+		oplabels.AddManagedByLabel(pvcLabels)
+
+		templates, err := newVolumeClaimTemplates(dc, pvcLabels)
+		if err != nil {
+			return nil, err
+		}
+
+		for ordinal := 0; ordinal < sts.NodeCount; ordinal++ {
+			podName := fmt.Sprintf("%s-%d", sts.Name, ordinal)
+			for _, template := range templates {
+				pvc := *template.DeepCopy()
+				pvc.Name = fmt.Sprintf("%s-%s", template.Name, podName)
+				pvc.Namespace = dc.Namespace
+				pvcs = append(pvcs, pvc)
+			}
+		}
+	}
+
+	return pvcs, nil
+}
+
 // Create a statefulset object for the Datacenter.
 // We have to account for the fact that they might use the old managed-by label value
 // (oplabels.ManagedByLabelDefunctValue) for CassandraDatacenters originally
@@ -115,39 +175,16 @@ func newStatefulSetForCassandraDatacenter(
 
 	statefulSetSelectorLabels := dc.GetRackLabels(rackName)
 
-	var volumeClaimTemplates []corev1.PersistentVolumeClaim
-
 	nodeAffinityLabels, nodeAffinityLabelsConfigurationError := rackNodeAffinitylabels(dc, rackName)
 	if nodeAffinityLabelsConfigurationError != nil {
 		return nil, nodeAffinityLabelsConfigurationError
 	}
 
-	// Add storage
-	if dc.Spec.StorageConfig.CassandraDataVolumeClaimSpec == nil {
-		err := fmt.Errorf("StorageConfig.cassandraDataVolumeClaimSpec is required")
+	volumeClaimTemplates, err := newVolumeClaimTemplates(dc, pvcLabels)
+	if err != nil {
 		return nil, err
 	}
 
-	volumeClaimTemplates = []corev1.PersistentVolumeClaim{{
-		ObjectMeta: metav1.ObjectMeta{
-			Labels: pvcLabels,
-			Name:   PvcName,
-		},
-		Spec: *dc.Spec.StorageConfig.CassandraDataVolumeClaimSpec,
-	}}
-
-	for _, storage := range dc.Spec.StorageConfig.AdditionalVolumes {
-		pvc := corev1.PersistentVolumeClaim{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:   storage.Name,
-				Labels: pvcLabels,
-			},
-			Spec: storage.PVCSpec,
-		}
-
-		volumeClaimTemplates = append(volumeClaimTemplates, pvc)
-	}
-
 	nsName := newNamespacedNameForStatefulSet(dc, rackName)
 
 	template, err := buildPodTemplateSpec(dc, nodeAffinityLabels, rackName)
@@ -173,7 +210,7 @@ func newStatefulSetForCassandraDatacenter(
 				MatchLabels: statefulSetSelectorLabels,
 			},
 			Replicas:             &replicaCountInt32,
-			ServiceName:          dc.GetAllPodsServiceName(),
+			ServiceName:          dc.GetStatefulSetServiceName(),
 			PodManagementPolicy:  appsv1.ParallelPodManagement,
 			Template:             *template,
 			VolumeClaimTemplates: volumeClaimTemplates,
@@ -194,3 +231,31 @@ func newStatefulSetForCassandraDatacenter(
 
 	return result, nil
 }
+
+// getServerCommandAndArgs returns the command, args and MGMT_API_* env vars of the container
+// launching the management API, which wraps the server, as set in the pod template of the
+// statefulset the operator builds for the rack. The command and args are empty unless set
+// through PodTemplateSpec, so that the image entrypoint starts the server, and the
+// ManagementApiAuth TLS settings reach the launcher as MGMT_API_TLS_* env vars.
+func getServerCommandAndArgs(dc *api.CassandraDatacenter, rackName string) ([]string, []string, []corev1.EnvVar, error) {
+	sts, err := newStatefulSetForCassandraDatacenter(nil, rackName, dc, 0, false)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	containerName := dc.GetManagementApiContainerName()
+	for _, container := range sts.Spec.Template.Spec.Containers {
+		if container.Name != containerName {
+			continue
+		}
+		env := []corev1.EnvVar{}
+		for _, envVar := range container.Env {
+			if strings.HasPrefix(envVar.Name, "MGMT_API_") {
+				env = append(env, envVar)
+			}
+		}
+		return container.Command, container.Args, env, nil
+	}
+
+	return nil, nil, nil, fmt.Errorf("could not find the %s container", containerName)
+}
@@ -11,6 +11,7 @@ import (
 	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 func Test_newStatefulSetForCassandraDatacenter(t *testing.T) {
@@ -367,3 +368,100 @@ func int64Ptr(n int64) *int64 {
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func TestGetExpectedPersistentVolumeClaims(t *testing.T) {
+	storageClassName := "standard"
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc1",
+			Namespace: "test",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "c1",
+			Size:          3,
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			Racks:         []api.Rack{{Name: "r1"}, {Name: "r2"}},
+			StorageConfig: api.StorageConfig{
+				CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+					StorageClassName: &storageClassName,
+				},
+				AdditionalVolumes: api.AdditionalVolumesSlice{
+					{
+						Name:      "commitlog",
+						MountPath: "/var/lib/cassandra/commitlog",
+					},
+				},
+			},
+		},
+	}
+
+	pvcs, err := GetExpectedPersistentVolumeClaims(dc)
+	assert.NoError(t, err)
+
+	names := []string{}
+	for _, pvc := range pvcs {
+		names = append(names, pvc.Name)
+		assert.Equal(t, "test", pvc.Namespace)
+	}
+	assert.Equal(t, []string{
+		"server-data-c1-dc1-r1-sts-0",
+		"commitlog-c1-dc1-r1-sts-0",
+		"server-data-c1-dc1-r1-sts-1",
+		"commitlog-c1-dc1-r1-sts-1",
+		"server-data-c1-dc1-r2-sts-0",
+		"commitlog-c1-dc1-r2-sts-0",
+	}, names)
+
+	// The claims match the volume claim templates of the statefulset
+	sts, err := newStatefulSetForCassandraDatacenter(nil, "r2", dc, 1, false)
+	assert.NoError(t, err)
+	for i, template := range sts.Spec.VolumeClaimTemplates {
+		assert.Equal(t, template.Labels, pvcs[4+i].Labels)
+		assert.Equal(t, template.Spec, pvcs[4+i].Spec)
+	}
+}
+
+func Test_getServerCommandAndArgs(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc1",
+			Namespace: "ns",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "cluster1",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			StorageConfig: api.StorageConfig{
+				CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{},
+			},
+		},
+	}
+
+	command, args, env, err := getServerCommandAndArgs(dc, "default")
+	assert.NoError(t, err)
+	assert.Empty(t, command)
+	assert.Empty(t, args)
+	for _, envVar := range env {
+		assert.NotContains(t, envVar.Name, "MGMT_API_TLS")
+	}
+
+	dc.Spec.ManagementApiAuth.Manual = &api.ManagementApiAuthManualConfig{
+		ClientSecretName: "client-secret",
+		ServerSecretName: "server-secret",
+	}
+	command, args, env, err = getServerCommandAndArgs(dc, "default")
+	assert.NoError(t, err)
+	assert.Empty(t, command)
+	assert.Empty(t, args)
+	assert.Contains(t, env, corev1.EnvVar{Name: "MGMT_API_TLS_CA_CERT_FILE", Value: "/management-api-certs/ca.crt"})
+	assert.Contains(t, env, corev1.EnvVar{Name: "MGMT_API_TLS_CERT_FILE", Value: "/management-api-certs/tls.crt"})
+	assert.Contains(t, env, corev1.EnvVar{Name: "MGMT_API_TLS_KEY_FILE", Value: "/management-api-certs/tls.key"})
+
+	// With the sidecar the management API container gets the TLS settings
+	dc.Spec.ManagementApiMode = api.ManagementApiModeSidecar
+	dc.Spec.ManagementApiImage = "example/mgmtapi:1.0.0"
+	_, _, env, err = getServerCommandAndArgs(dc, "default")
+	assert.NoError(t, err)
+	assert.Contains(t, env, corev1.EnvVar{Name: "MGMT_API_TLS_CERT_FILE", Value: "/management-api-certs/tls.crt"})
+}
@@ -29,7 +29,7 @@ func (rc *ReconciliationContext) CalculateRackInfoForDecomm(currentSize int) ([]
 	}
 
 	var decommRackInfo []*RackInformation
-	rackNodeCounts := api.SplitRacks(desiredSize, rackCount)
+	rackNodeCounts := rc.Datacenter.GetRackNodeCounts(desiredSize)
 
 	for rackIndex, currentRack := range racks {
 		nextRack := &RackInformation{}
@@ -117,7 +117,7 @@ func (rc *ReconciliationContext) DecommissionNodeOnRack(rackName string, epData
 	for _, pod := range rc.dcPods {
 		podRack := pod.Labels[api.RackLabel]
 		if podRack == rackName && strings.HasSuffix(pod.Name, lastPodSuffix) {
-			mgmtApiUp := isMgmtApiRunning(pod)
+			mgmtApiUp := isMgmtApiRunning(rc.Datacenter, pod)
 			if !mgmtApiUp {
 				return fmt.Errorf("Management API is not up on node that we are trying to decommission")
 			}
@@ -126,6 +126,10 @@ func (rc *ReconciliationContext) DecommissionNodeOnRack(rackName string, epData
 				return err
 			}
 
+			if err := rc.replaceLeavingSeed(pod); err != nil {
+				return err
+			}
+
 			if err := rc.NodeMgmtClient.CallDecommissionNodeEndpoint(pod); err != nil {
 				rc.ReqLogger.Info(fmt.Sprintf("Error from decommission attempt. This is only an attempt and can"+
 					" fail it will be retried later if decomission has not started. Error: %v", err))
@@ -134,6 +138,7 @@ func (rc *ReconciliationContext) DecommissionNodeOnRack(rackName string, epData
 			rc.ReqLogger.Info("Marking node as decommissioning")
 			patch := client.MergeFrom(pod.DeepCopy())
 			pod.Labels[api.CassNodeState] = stateDecommissioning
+			delete(pod.Labels, api.SeedNodeLabel)
 			if err := rc.Client.Patch(rc.Ctx, pod, patch); err != nil {
 				return err
 			}
@@ -149,6 +154,25 @@ func (rc *ReconciliationContext) DecommissionNodeOnRack(rackName string, epData
 	return fmt.Errorf("Could not find pod to decommission on rack %s", rackName)
 }
 
+// replaceLeavingSeed labels the pod found by findSeedReplacementPod as seed before the
+// given pod leaves the ring, so that its rack keeps a seed while it decommissions
+func (rc *ReconciliationContext) replaceLeavingSeed(leavingPod *corev1.Pod) error {
+	replacement := findSeedReplacementPod(rc.Datacenter, rc.dcPods, leavingPod)
+	if replacement == nil {
+		return nil
+	}
+
+	patch := client.MergeFrom(replacement.DeepCopy())
+	replacement.Labels[api.SeedNodeLabel] = "true"
+	if err := rc.Client.Patch(rc.Ctx, replacement, patch); err != nil {
+		return err
+	}
+
+	rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.LabeledPodAsSeed,
+		"Labeled as seed node pod %s replacing %s", replacement.Name, leavingPod.Name)
+	return nil
+}
+
 // Wait for decommissioning nodes to finish before continuing to reconcile
 func (rc *ReconciliationContext) CheckDecommissioningNodes(epData httphelper.CassMetadataEndpoints) result.ReconcileResult {
 	if rc.Datacenter.GetConditionStatus(api.DatacenterScalingDown) != corev1.ConditionTrue {
@@ -5,6 +5,8 @@ package reconciliation
 
 import (
 	"fmt"
+	"strings"
+
 	"github.com/k8ssandra/cass-operator/operator/pkg/oplabels"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -26,6 +28,7 @@ import (
 	"github.com/k8ssandra/cass-operator/operator/internal/result"
 	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
 	"github.com/k8ssandra/cass-operator/operator/pkg/dynamicwatch"
+	"github.com/k8ssandra/cass-operator/operator/pkg/events"
 	"github.com/k8ssandra/cass-operator/operator/pkg/httphelper"
 	"github.com/k8ssandra/cass-operator/operator/pkg/utils"
 	"github.com/k8ssandra/cass-operator/operator/pkg/psp"
@@ -244,7 +247,7 @@ func (r *ReconcileCassandraDatacenter) Reconcile(request reconcile.Request) (rec
 
 	if err := rc.isValid(rc.Datacenter); err != nil {
 		logger.Error(err, "CassandraDatacenter resource is invalid")
-		rc.Recorder.Eventf(rc.Datacenter, "Warning", "ValidationFailed", err.Error())
+		rc.Recorder.Event(rc.Datacenter, corev1.EventTypeWarning, events.ValidationFailed, err.Error())
 		return result.Error(err).Output()
 	}
 
@@ -269,15 +272,15 @@ func (r *ReconcileCassandraDatacenter) Reconcile(request reconcile.Request) (rec
 	res, err := rc.calculateReconciliationActions()
 	if err != nil {
 		logger.Error(err, "calculateReconciliationActions returned an error")
-		rc.Recorder.Eventf(rc.Datacenter, "Warning", "ReconcileFailed", err.Error())
+		rc.Recorder.Event(rc.Datacenter, corev1.EventTypeWarning, events.ReconcileFailed, err.Error())
 	}
 	return res, err
 }
 
 func (rc *ReconciliationContext) addFinalizer() error {
-	if len(rc.Datacenter.GetFinalizers()) < 1 && rc.Datacenter.GetDeletionTimestamp() == nil {
+	if !rc.Datacenter.HasOperatorFinalizer() && rc.Datacenter.GetDeletionTimestamp() == nil {
 		rc.ReqLogger.Info("Adding Finalizer for the CassandraDatacenter")
-		rc.Datacenter.SetFinalizers([]string{"finalizer.cassandra.datastax.com"})
+		rc.Datacenter.SetFinalizers(append(rc.Datacenter.GetFinalizers(), api.Finalizer))
 
 		// Update CR
 		err := rc.Client.Update(rc.Ctx, rc.Datacenter)
@@ -295,6 +298,11 @@ func (rc *ReconciliationContext) isValid(dc *api.CassandraDatacenter) error {
 	// Basic validation up here
 
 	// validate the required superuser
+	if dc.EnforcesSuperuserConfig() {
+		if err := dc.ValidateSuperuserConfig(); err != nil {
+			errs = append(errs, err)
+		}
+	}
 	errs = append(errs, rc.validateSuperuserSecret()...)
 
 	// validate any other defined users
@@ -302,6 +310,9 @@ func (rc *ReconciliationContext) isValid(dc *api.CassandraDatacenter) error {
 
 	// Validate Management API config
 	errs = append(errs, httphelper.ValidateManagementApiConfig(dc, rc.Client, rc.Ctx)...)
+
+	// validate the env vars of the EnvFrom sources
+	errs = append(errs, rc.validateEnvFromSources()...)
 	if len(errs) > 0 {
 		return errs[0]
 	}
@@ -325,6 +336,59 @@ func (rc *ReconciliationContext) isValid(dc *api.CassandraDatacenter) error {
 	return nil
 }
 
+// validateEnvFromSources checks that the ConfigMaps and Secrets of the EnvFrom sources do
+// not define env vars which are set by the operator. Sources which do not exist are left
+// for the kubelet to report.
+func (rc *ReconciliationContext) validateEnvFromSources() []error {
+	dc := rc.Datacenter
+	errs := []error{}
+
+	for _, source := range dc.Spec.EnvFrom {
+		var kind, name string
+		keys := []string{}
+
+		if source.ConfigMapRef != nil {
+			kind, name = "ConfigMap", source.ConfigMapRef.Name
+			configMap := &corev1.ConfigMap{}
+			err := rc.Client.Get(rc.Ctx, types.NamespacedName{Name: name, Namespace: dc.Namespace}, configMap)
+			if err != nil {
+				if !errors.IsNotFound(err) {
+					errs = append(errs, fmt.Errorf("Validation of envFrom ConfigMap %s failed due to an error: %w", name, err))
+				}
+				continue
+			}
+			for key := range configMap.Data {
+				keys = append(keys, key)
+			}
+			for key := range configMap.BinaryData {
+				keys = append(keys, key)
+			}
+		} else if source.SecretRef != nil {
+			kind, name = "Secret", source.SecretRef.Name
+			secret := &corev1.Secret{}
+			err := rc.Client.Get(rc.Ctx, types.NamespacedName{Name: name, Namespace: dc.Namespace}, secret)
+			if err != nil {
+				if !errors.IsNotFound(err) {
+					errs = append(errs, fmt.Errorf("Validation of envFrom Secret %s failed due to an error: %w", name, err))
+				}
+				continue
+			}
+			for key := range secret.Data {
+				keys = append(keys, key)
+			}
+		} else {
+			continue
+		}
+
+		if reserved := findReservedEnvVars(dc, source.Prefix, keys); len(reserved) > 0 {
+			errs = append(errs, fmt.Errorf("envFrom %s %s defines env vars set by the operator: %s",
+				kind, name, strings.Join(reserved, ", ")))
+		}
+	}
+
+	return errs
+}
+
 // NewReconciler returns a new reconcile.Reconciler
 func NewReconciler(mgr manager.Manager) reconcile.Reconciler {
 	client := mgr.GetClient()
@@ -392,3 +392,51 @@ func TestReconcile_CassandraDatacenterToBeDeleted(t *testing.T) {
 		t.Error("Reconcile did not return an empty result.")
 	}
 }
+
+func TestValidateEnvFromSources(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "jvm-tuning",
+			Namespace: rc.Datacenter.Namespace,
+		},
+		Data: map[string]string{
+			"EXPLICIT_START": "false",
+			"HEAP_NEWSIZE":   "200M",
+		},
+	}
+	assert.NoError(t, rc.Client.Create(rc.Ctx, configMap))
+
+	// Missing sources are not reported
+	rc.Datacenter.Spec.EnvFrom = []corev1.EnvFromSource{
+		{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "jvm-tuning"}}},
+		{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "missing"}}},
+	}
+	assert.Empty(t, rc.validateEnvFromSources())
+
+	// With the prefix a key becomes an env var set by the operator
+	rc.Datacenter.Spec.EnvFrom[0].Prefix = "MGMT_API_"
+	errs := rc.validateEnvFromSources()
+	assert.Len(t, errs, 1)
+	assert.EqualError(t, errs[0], "envFrom ConfigMap jvm-tuning defines env vars set by the operator: MGMT_API_EXPLICIT_START")
+}
+
+func TestIsValid_SuperuserConfig(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	dc := rc.Datacenter
+	superuserSecret := dc.GetSuperuserSecretNamespacedName().Name
+	dc.Spec.Users = []api.CassandraUser{{SecretName: superuserSecret, Superuser: true}}
+
+	err := rc.isValid(dc)
+	assert.EqualError(t, err, fmt.Sprintf("users lists the generated superuser secret %s, which the operator already upserts", superuserSecret))
+
+	// Like the webhook, the controller keeps reconciling existing datacenters, here up to
+	// the user secret check
+	dc.Status.SuperUserUpserted = metav1.Now()
+	err = rc.isValid(dc)
+	assert.EqualError(t, err, fmt.Sprintf("Validation of user secret failed due to an error: secrets \"%s\" not found", superuserSecret))
+}
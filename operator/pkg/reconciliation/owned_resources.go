@@ -0,0 +1,67 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package reconciliation
+
+import (
+	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// GetOwnedResources returns references to the resources the operator creates for the
+// datacenter. Deleting the datacenter deletes the PVCs through the finalizer and the
+// others through their owner references, except for the generated superuser secret,
+// which is shared by the datacenters of the cluster and has to be deleted by hand. Pods
+// are left out, as the statefulsets own them.
+func GetOwnedResources(dc *api.CassandraDatacenter) ([]corev1.ObjectReference, error) {
+	var refs []corev1.ObjectReference
+	add := func(apiVersion, kind, name string) {
+		refs = append(refs, corev1.ObjectReference{
+			APIVersion: apiVersion,
+			Kind:       kind,
+			Namespace:  dc.Namespace,
+			Name:       name,
+		})
+	}
+
+	for _, expected := range dc.GetExpectedStatefulSets() {
+		add("apps/v1", "StatefulSet", expected.Name)
+	}
+
+	pvcs, err := GetExpectedPersistentVolumeClaims(dc)
+	if err != nil {
+		return nil, err
+	}
+	for _, pvc := range pvcs {
+		add("v1", "PersistentVolumeClaim", pvc.Name)
+	}
+
+	serviceNames := []string{
+		dc.GetDatacenterServiceName(),
+		dc.GetSeedServiceName(),
+		dc.GetAllPodsServiceName(),
+	}
+	if len(dc.Spec.AdditionalSeeds) > 0 {
+		serviceNames = append(serviceNames, dc.GetAdditionalSeedsServiceName())
+	}
+	if dc.IsNodePortEnabled() {
+		serviceNames = append(serviceNames, dc.GetNodePortServiceName())
+	}
+	if dc.Spec.ManagementApiService {
+		serviceNames = append(serviceNames, dc.GetManagementApiServiceName())
+	}
+	for _, name := range serviceNames {
+		add("v1", "Service", name)
+	}
+
+	add("policy/v1beta1", "PodDisruptionBudget", newPodDisruptionBudgetForDatacenter(dc).Name)
+
+	if dc.ShouldGenerateSuperuserSecret() {
+		add("v1", "Secret", dc.GetSuperuserSecretNamespacedName().Name)
+	}
+	if len(dc.Spec.ConfigSecret) > 0 {
+		add("v1", "Secret", getDatacenterConfigSecretName(dc))
+	}
+
+	return refs, nil
+}
@@ -0,0 +1,59 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package reconciliation
+
+import (
+	"testing"
+
+	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetOwnedResources(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc1",
+			Namespace: "test",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "cluster1",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			Size:          2,
+			ConfigSecret:  "my-config",
+			Racks:         []api.Rack{{Name: "r1"}, {Name: "r2"}},
+			StorageConfig: api.StorageConfig{
+				CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{corev1.ResourceStorage: resource.MustParse("1Gi")},
+					},
+				},
+			},
+		},
+	}
+
+	refs, err := GetOwnedResources(dc)
+	assert.NoError(t, err)
+
+	var names []string
+	for _, ref := range refs {
+		assert.Equal(t, "test", ref.Namespace)
+		names = append(names, ref.Kind+"/"+ref.Name)
+	}
+	assert.Equal(t, []string{
+		"StatefulSet/cluster1-dc1-r1-sts",
+		"StatefulSet/cluster1-dc1-r2-sts",
+		"PersistentVolumeClaim/server-data-cluster1-dc1-r1-sts-0",
+		"PersistentVolumeClaim/server-data-cluster1-dc1-r2-sts-0",
+		"Service/cluster1-dc1-service",
+		"Service/cluster1-seed-service",
+		"Service/cluster1-dc1-all-pods-service",
+		"PodDisruptionBudget/dc1-pdb",
+		"Secret/cluster1-superuser",
+		"Secret/cluster1-dc1-config",
+	}, names)
+}
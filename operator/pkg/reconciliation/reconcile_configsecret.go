@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/k8ssandra/cass-operator/operator/internal/result"
 	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/operator/pkg/events"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -42,6 +43,8 @@ func (rc *ReconciliationContext) CheckConfigSecret() result.ReconcileResult {
 	config, err := getConfigFromConfigSecret(rc.Datacenter, secret)
 	if err != nil {
 		rc.ReqLogger.Error(err, "failed to get json config from secret", "ConfigSecret", rc.Datacenter.Spec.ConfigSecret)
+		rc.Recorder.Event(rc.Datacenter, corev1.EventTypeWarning, events.ConfigRenderingFailed,
+			events.DatacenterMessage(rc.Datacenter, "Failed to render config from secret %s: %v", rc.Datacenter.Spec.ConfigSecret, err))
 		return result.Error(err)
 	}
 
@@ -35,6 +35,8 @@ func (rc *ReconciliationContext) ProcessDeletion() result.ReconcileResult {
 		rc.ReqLogger.Error(err, "Failed to remove dynamic secret watches for CassandraDatacenter")
 	}
 
+	forgetConfigBuilderInput(rc.Datacenter)
+
 	if err := rc.deletePVCs(); err != nil {
 		rc.ReqLogger.Error(err, "Failed to delete PVCs for CassandraDatacenter")
 		return result.Error(err)
@@ -47,7 +49,7 @@ func (rc *ReconciliationContext) ProcessDeletion() result.ReconcileResult {
 	}
 
 	// Update finalizer to allow delete of CassandraDatacenter
-	rc.Datacenter.SetFinalizers(nil)
+	rc.Datacenter.SetFinalizers(utils.RemoveValueFromStringArray(rc.Datacenter.GetFinalizers(), api.Finalizer))
 
 	// Update CassandraDatacenter
 	if err := rc.Client.Update(rc.Ctx, rc.Datacenter); err != nil {
@@ -4,10 +4,13 @@
 package reconciliation
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	appsv1 "k8s.io/api/apps/v1"
@@ -64,25 +67,14 @@ func (rc *ReconciliationContext) CalculateRackInformation() error {
 		nodeCount = 0
 	}
 
-	// 3 seeds per datacenter (this could be two, but we would like three seeds per cluster
-	// and it's not easy for us to know if we're in a multi DC cluster in this part of the code)
-	// OR all of the nodes, if there's less than 3
-	// OR one per rack if there are four or more racks
-	seedCount := 3
-	if nodeCount < 3 {
-		seedCount = nodeCount
-	} else if rackCount > 3 {
-		seedCount = rackCount
-	}
-
 	var desiredRackInformation []*RackInformation
 
 	if rackCount < 1 {
 		return fmt.Errorf("assertion failed! rackCount should not possibly be zero here")
 	}
 
-	rackSeedCounts := api.SplitRacks(seedCount, rackCount)
-	rackNodeCounts := api.SplitRacks(nodeCount, rackCount)
+	rackNodeCounts := rc.Datacenter.GetRackNodeCounts(nodeCount)
+	rackSeedCounts := rc.Datacenter.GetRackSeedCounts(rackNodeCounts)
 
 	for rackIndex, currentRack := range racks {
 		nextRack := &RackInformation{}
@@ -177,14 +169,123 @@ func (rc *ReconciliationContext) desiredStatefulSetForExistingStatefulSet(sts *a
 	return newStatefulSetForCassandraDatacenter(sts, rackName, dc, replicas, usesDefunct)
 }
 
+// getStatefulSetPartition returns the rolling update partition of the statefulset, 0 when
+// none is set
+func getStatefulSetPartition(sts *appsv1.StatefulSet) int32 {
+	rollingUpdate := sts.Spec.UpdateStrategy.RollingUpdate
+	if rollingUpdate == nil || rollingUpdate.Partition == nil {
+		return 0
+	}
+	return *rollingUpdate.Partition
+}
+
+// loggedConfigBuilderInputs holds, per datacenter, the hash of the config builder input
+// logged last, so that the input is logged once per config rather than on every reconcile.
+// Entries are removed by forgetConfigBuilderInput when the datacenter is deleted.
+var loggedConfigBuilderInputs sync.Map
+
+// shouldLogConfigBuilderInput returns whether the config builder input of the given hash was
+// not logged yet for the datacenter, and records it as logged
+func shouldLogConfigBuilderInput(dc *api.CassandraDatacenter, configHash string) bool {
+	key := types.NamespacedName{Namespace: dc.Namespace, Name: dc.Name}.String()
+	if previous, ok := loggedConfigBuilderInputs.Load(key); ok && previous == configHash {
+		return false
+	}
+	loggedConfigBuilderInputs.Store(key, configHash)
+	return true
+}
+
+// forgetConfigBuilderInput removes the hash of the config builder input logged last for the
+// datacenter
+func forgetConfigBuilderInput(dc *api.CassandraDatacenter) {
+	loggedConfigBuilderInputs.Delete(types.NamespacedName{Namespace: dc.Namespace, Name: dc.Name}.String())
+}
+
+// logConfigBuilderInput logs the config passed to the config builder whenever it changed,
+// indented so that it stays readable in the operator logs and with its secrets redacted
+func (rc *ReconciliationContext) logConfigBuilderInput() {
+	dc := rc.Datacenter
+	configHash, err := dc.GetConfigHash()
+	if err != nil {
+		rc.ReqLogger.Error(err, "Failed to render the config builder input")
+		return
+	}
+	if !shouldLogConfigBuilderInput(dc, configHash) {
+		return
+	}
+
+	if len(dc.Spec.ConfigSecret) > 0 {
+		rc.ReqLogger.Info("Config builder input is read from the config secret", "configSecret", dc.Spec.ConfigSecret)
+		return
+	}
+
+	config, err := dc.GetConfigAsJSON(dc.Spec.Config)
+	if err != nil {
+		rc.ReqLogger.Error(err, "Failed to render the config builder input")
+		return
+	}
+
+	redacted, err := redactConfigBuilderInput(config)
+	if err != nil {
+		rc.ReqLogger.Error(err, "Failed to redact the config builder input")
+		return
+	}
+	rc.ReqLogger.Info("Config builder input:\n" + redacted)
+}
+
+// redactConfigBuilderInput returns the config indented, with the values of the keys naming
+// a password or secret replaced
+func redactConfigBuilderInput(config string) (string, error) {
+	decoder := json.NewDecoder(strings.NewReader(config))
+	decoder.UseNumber()
+
+	var parsed interface{}
+	if err := decoder.Decode(&parsed); err != nil {
+		return "", err
+	}
+
+	var indented strings.Builder
+	encoder := json.NewEncoder(&indented)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(redactSecretValues(parsed)); err != nil {
+		return "", err
+	}
+	return strings.TrimSuffix(indented.String(), "\n"), nil
+}
+
+func redactSecretValues(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, item := range v {
+			lowerKey := strings.ToLower(key)
+			if strings.Contains(lowerKey, "password") || strings.Contains(lowerKey, "secret") {
+				v[key] = "<redacted>"
+			} else {
+				v[key] = redactSecretValues(item)
+			}
+		}
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactSecretValues(item)
+		}
+	}
+	return value
+}
+
 func (rc *ReconciliationContext) CheckRackPodTemplate() result.ReconcileResult {
 	logger := rc.ReqLogger
 	dc := rc.Datacenter
 	logger.Info("starting CheckRackPodTemplate()")
 
+	if dc.Spec.DebugConfigBuilder {
+		rc.logConfigBuilderInput()
+	}
+
 	for idx := range rc.desiredRackInformation {
 		rackName := rc.desiredRackInformation[idx].RackName
-		if dc.Spec.CanaryUpgrade && idx > 0 {
+		updateStrategy := dc.GetRackUpdateStrategy(idx, rc.desiredRackInformation[idx].NodeCount)
+		if updateStrategy.Paused {
 			logger.
 				WithValues("rackName", rackName).
 				Info("Skipping rack because CanaryUpgrade is turned on")
@@ -212,7 +313,10 @@ func (rc *ReconciliationContext) CheckRackPodTemplate() result.ReconcileResult {
 
 		needsUpdate := false
 
-		if !utils.ResourcesHaveSameHash(statefulSet, desiredSts) {
+		// A partition left over from a canary upgrade has to be reset as well, even when
+		// the pod template has not changed since
+		partition := dc.GetRackCanaryPartition(idx, rc.desiredRackInformation[idx].NodeCount)
+		if !utils.ResourcesHaveSameHash(statefulSet, desiredSts) || getStatefulSetPartition(statefulSet) != partition {
 			logger.
 				WithValues("rackName", rackName).
 				Info("statefulset needs an update")
@@ -224,17 +328,11 @@ func (rc *ReconciliationContext) CheckRackPodTemplate() result.ReconcileResult {
 			desiredSts.Labels = utils.MergeMap(map[string]string{}, statefulSet.Labels, desiredSts.Labels)
 			desiredSts.Annotations = utils.MergeMap(map[string]string{}, statefulSet.Annotations, desiredSts.Annotations)
 
-			if dc.Spec.CanaryUpgrade {
-				var partition int32
-				if dc.Spec.CanaryUpgradeCount == 0 || dc.Spec.CanaryUpgradeCount > int32(rc.desiredRackInformation[idx].NodeCount) {
-					partition = int32(rc.desiredRackInformation[idx].NodeCount)
-				} else {
-					partition = int32(rc.desiredRackInformation[idx].NodeCount) - dc.Spec.CanaryUpgradeCount
-				}
+			if updateStrategy.Partition != nil {
 				strategy := appsv1.StatefulSetUpdateStrategy{
 					Type: appsv1.RollingUpdateStatefulSetStrategyType,
 					RollingUpdate: &appsv1.RollingUpdateStatefulSetStrategy{
-						Partition: &partition,
+						Partition: updateStrategy.Partition,
 					},
 				}
 				desiredSts.Spec.UpdateStrategy = strategy
@@ -485,7 +583,7 @@ func (rc *ReconciliationContext) CheckRackStoppedState() result.ReconcileResult
 			nodeDrainErrors := 0
 
 			for _, pod := range rackPods {
-				if isMgmtApiRunning(pod) {
+				if isMgmtApiRunning(rc.Datacenter, pod) {
 					nodesDrained++
 					err := rc.NodeMgmtClient.CallDrainEndpoint(pod)
 					// if we got an error during drain, just log it and count it
@@ -522,10 +620,19 @@ func (rc *ReconciliationContext) CheckRackStoppedState() result.ReconcileResult
 // checkSeedLabels loops over all racks and makes sure that the proper pods are labelled as seeds.
 func (rc *ReconciliationContext) checkSeedLabels() (int, error) {
 	rc.ReqLogger.Info("reconcile_racks::CheckSeedLabels")
+	desiredSeedCount := 0
+	for _, rackInfo := range rc.desiredRackInformation {
+		desiredSeedCount += rackInfo.SeedCount
+	}
+	seeds := map[string]bool{}
+	for _, podName := range selectZoneAwareSeeds(rc.Datacenter, rc.dcPods, desiredSeedCount) {
+		seeds[podName] = true
+	}
+
 	seedCount := 0
 	for idx := range rc.desiredRackInformation {
 		rackInfo := rc.desiredRackInformation[idx]
-		n, err := rc.labelSeedPods(rackInfo)
+		n, err := rc.labelSeedPods(rackInfo, seeds)
 		seedCount += n
 		if err != nil {
 			return 0, err
@@ -663,20 +770,20 @@ func hasPodPotentiallyBootstrapped(pod *corev1.Pod, nodeStatuses api.CassandraSt
 	return false
 }
 
-func findAllPodsNotReadyAndPotentiallyBootstrapped(dcPods []*corev1.Pod, nodeStatuses api.CassandraStatusMap) []*corev1.Pod {
+func findAllPodsNotReadyAndPotentiallyBootstrapped(dc *api.CassandraDatacenter, dcPods []*corev1.Pod, nodeStatuses api.CassandraStatusMap) []*corev1.Pod {
 	downPods := []*corev1.Pod{}
 	for _, pod := range dcPods {
-		if !isServerReady(pod) && hasPodPotentiallyBootstrapped(pod, nodeStatuses) {
+		if !isServerReady(dc, pod) && hasPodPotentiallyBootstrapped(pod, nodeStatuses) {
 			downPods = append(downPods, pod)
 		}
 	}
 	return downPods
 }
 
-func findAllPodsNotReady(dcPods []*corev1.Pod) []*corev1.Pod {
+func findAllPodsNotReady(dc *api.CassandraDatacenter, dcPods []*corev1.Pod) []*corev1.Pod {
 	downPods := []*corev1.Pod{}
 	for _, pod := range dcPods {
-		if !isServerReady(pod) {
+		if !isServerReady(dc, pod) {
 			downPods = append(downPods, pod)
 		}
 	}
@@ -850,8 +957,8 @@ func (rc *ReconciliationContext) upsertUser(user api.CassandraUser) error {
 
 	err = rc.NodeMgmtClient.CallCreateRoleEndpoint(
 		pod,
-		string(secret.Data["username"]),
-		string(secret.Data["password"]),
+		string(secret.Data[api.SuperuserSecretUsernameKey]),
+		string(secret.Data[api.SuperuserSecretPasswordKey]),
 		user.Superuser)
 
 	return err
@@ -899,7 +1006,14 @@ func (rc *ReconciliationContext) CreateUsers() result.ReconcileResult {
 	}
 
 	// make sure the default superuser secret exists
-	_, err = rc.retrieveSuperuserSecretOrCreateDefault()
+	superuserSecret, err := rc.retrieveSuperuserSecretOrCreateDefault()
+
+	// The superuser is upserted on every pass regardless, so that it is recreated when it
+	// was lost in the cluster, e.g. after system_auth was restored
+	if err == nil && superuserSecret != nil && dc.SuperuserSecretNeedsUpsert(superuserSecret) {
+		rc.ReqLogger.Info("superuser secret changed since the last upsert",
+			"secretName", superuserSecret.Name)
+	}
 
 	users := rc.GetUsers()
 
@@ -911,8 +1025,17 @@ func (rc *ReconciliationContext) CreateUsers() result.ReconcileResult {
 		}
 	}
 
-	rc.Recorder.Eventf(dc, corev1.EventTypeNormal, events.CreatedUsers,
-		"Created users")
+	rc.Recorder.Event(dc, corev1.EventTypeNormal, events.CreatedUsers,
+		events.DatacenterMessage(dc, "Created users"))
+
+	if len(dc.Spec.SystemAuthReplication) > 0 {
+		// We will call mgmt API on the first pod
+		err := rc.NodeMgmtClient.AlterKeyspace(rc.dcPods[0], "system_auth", dc.GetSystemAuthReplicationSettings())
+		if err != nil {
+			rc.ReqLogger.Error(err, "error altering system_auth replication")
+			return result.Error(err)
+		}
+	}
 
 	// For backwards compatibility
 	rc.Recorder.Eventf(dc, corev1.EventTypeNormal, events.CreatedSuperuser,
@@ -971,7 +1094,7 @@ func (rc *ReconciliationContext) UpdateCassandraNodeStatus() error {
 			nodeStatus = api.CassandraNodeStatus{}
 		}
 
-		if pod.Status.PodIP != "" && isMgmtApiRunning(pod) {
+		if pod.Status.PodIP != "" && isMgmtApiRunning(rc.Datacenter, pod) {
 			// Getting the HostID requires a call to the node management API which is
 			// moderately expensive, so if we already have a HostID, don't bother. This
 			// would only change if something has gone horribly horribly wrong.
@@ -993,6 +1116,16 @@ func (rc *ReconciliationContext) UpdateCassandraNodeStatus() error {
 		dc.Status.NodeStatuses[pod.Name] = nodeStatus
 	}
 
+	// Entries are kept for pods that still exist, even if they are no longer expected,
+	// as those may still be decommissioning.
+	knownPodNames := dc.GetPodNames()
+	for _, pod := range rc.dcPods {
+		knownPodNames = append(knownPodNames, pod.Name)
+	}
+	if pruned := dc.Status.PruneNodeStatuses(knownPodNames); len(pruned) > 0 {
+		logger.Info("Pruned stale node statuses", "pods", pruned)
+	}
+
 	return nil
 }
 
@@ -1151,8 +1284,8 @@ func hasBeenXMinutesSinceReady(x int, pod *corev1.Pod) bool {
 	return false
 }
 
-func hasBeenXMinutesSinceStarted(x int, pod *corev1.Pod) bool {
-	if status := getCassContainerStatus(pod); status != nil {
+func hasBeenXMinutesSinceStarted(dc *api.CassandraDatacenter, x int, pod *corev1.Pod) bool {
+	if status := getMgmtApiContainerStatus(dc, pod); status != nil {
 		running := status.State.Running
 		if running != nil {
 			return hasBeenXMinutes(x, running.StartedAt.Time)
@@ -1161,8 +1294,8 @@ func hasBeenXMinutesSinceStarted(x int, pod *corev1.Pod) bool {
 	return false
 }
 
-func hasBeenXMinutesSinceTerminated(x int, pod *corev1.Pod) bool {
-	if status := getCassContainerStatus(pod); status != nil {
+func hasBeenXMinutesSinceTerminated(dc *api.CassandraDatacenter, x int, pod *corev1.Pod) bool {
+	if status := getMgmtApiContainerStatus(dc, pod); status != nil {
 		lastState := status.LastTerminationState
 		if lastState.Terminated != nil {
 			return hasBeenXMinutes(x, lastState.Terminated.FinishedAt.Time)
@@ -1171,9 +1304,12 @@ func hasBeenXMinutesSinceTerminated(x int, pod *corev1.Pod) bool {
 	return false
 }
 
-func getCassContainerStatus(pod *corev1.Pod) *corev1.ContainerStatus {
+// getMgmtApiContainerStatus returns the status of the pod container running the management
+// API, which also carries the readiness probe
+func getMgmtApiContainerStatus(dc *api.CassandraDatacenter, pod *corev1.Pod) *corev1.ContainerStatus {
+	containerName := dc.GetManagementApiContainerName()
 	for _, status := range pod.Status.ContainerStatuses {
-		if status.Name != "cassandra" {
+		if status.Name != containerName {
 			continue
 		}
 		return &status
@@ -1181,12 +1317,12 @@ func getCassContainerStatus(pod *corev1.Pod) *corev1.ContainerStatus {
 	return nil
 }
 
-func isNodeStuckAfterTerminating(pod *corev1.Pod) bool {
-	if isServerReady(pod) || isServerReadyToStart(pod) {
+func isNodeStuckAfterTerminating(dc *api.CassandraDatacenter, pod *corev1.Pod) bool {
+	if isServerReady(dc, pod) || isServerReadyToStart(pod) {
 		return false
 	}
 
-	return hasBeenXMinutesSinceTerminated(10, pod)
+	return hasBeenXMinutesSinceTerminated(dc, 10, pod)
 }
 
 func isNodeStuckAfterLosingReadiness(pod *corev1.Pod) bool {
@@ -1201,7 +1337,7 @@ func (rc *ReconciliationContext) getCassMetadataEndpoints() httphelper.CassMetad
 	for _, pod := range rc.clusterPods {
 		// Try to query the first ready pod we find.
 		// We won't get any endpoints back if no pods are ready yet.
-		if !isServerReady(pod) {
+		if !isServerReady(rc.Datacenter, pod) {
 			continue
 		}
 
@@ -1246,7 +1382,7 @@ func (rc *ReconciliationContext) deleteStuckNodes() (bool, error) {
 	for _, pod := range rc.dcPods {
 		shouldDelete := false
 		reason := ""
-		if isNodeStuckAfterTerminating(pod) {
+		if isNodeStuckAfterTerminating(rc.Datacenter, pod) {
 			reason = "Pod got stuck after Cassandra container terminated"
 			shouldDelete = true
 		} else if isNodeStuckAfterLosingReadiness(pod) {
@@ -1279,16 +1415,16 @@ func (rc *ReconciliationContext) isClusterHealthy() bool {
 	return true
 }
 
-// labelSeedPods iterates over all pods for a statefulset and makes sure the right number of
-// ready pods are labelled as seeds, so that they are picked up by the headless seed service
-// Returns the number of ready seeds.
-func (rc *ReconciliationContext) labelSeedPods(rackInfo *RackInformation) (int, error) {
+// labelSeedPods iterates over all pods for a statefulset and makes sure the pods selected as
+// seeds by selectZoneAwareSeeds are labelled as seeds, so that they are picked up by the
+// headless seed service, and no others. Returns the number of ready seeds.
+func (rc *ReconciliationContext) labelSeedPods(rackInfo *RackInformation, seeds map[string]bool) (int, error) {
 	logger := rc.ReqLogger.WithName("labelSeedPods")
 
 	rackLabels := rc.Datacenter.GetRackLabels(rackInfo.RackName)
 	rackPods := FilterPodListByLabels(rc.dcPods, rackLabels)
 	sort.SliceStable(rackPods, func(i, j int) bool {
-		return rackPods[i].Name < rackPods[j].Name
+		return podNameLess(rackPods[i].Name, rackPods[j].Name)
 	})
 	count := 0
 	for _, pod := range rackPods {
@@ -1297,10 +1433,9 @@ func (rc *ReconciliationContext) labelSeedPods(rackInfo *RackInformation) (int,
 		newLabels := make(map[string]string)
 		utils.MergeMap(newLabels, pod.GetLabels())
 
-		ready := isServerReady(pod)
 		starting := isServerStarting(pod)
 
-		isSeed := ready && count < rackInfo.SeedCount
+		isSeed := seeds[pod.Name] && isSeedCandidate(rc.Datacenter, pod)
 		currentVal := pod.GetLabels()[api.SeedNodeLabel]
 		if isSeed {
 			count++
@@ -1341,6 +1476,153 @@ func (rc *ReconciliationContext) labelSeedPods(rackInfo *RackInformation) (int,
 	return count, nil
 }
 
+// findSeedReplacementPod returns the pod that should be promoted to seed when removedPod is
+// permanently removed. A replacement is only needed if removedPod was a seed, or if its rack
+// would otherwise be left without a seed, so that every rack keeps at least one seed. The
+// replacement is the first ready non-seed pod of the same rack, ordered by StatefulSet
+// ordinal, and nil is returned if there is no such pod or no replacement is needed.
+func findSeedReplacementPod(dc *api.CassandraDatacenter, pods []*corev1.Pod, removedPod *corev1.Pod) *corev1.Pod {
+	rackName := removedPod.Labels[api.RackLabel]
+
+	rackPods := []*corev1.Pod{}
+	rackHasSeed := false
+	for _, pod := range pods {
+		if pod.Name == removedPod.Name || pod.Labels[api.RackLabel] != rackName {
+			continue
+		}
+		if pod.Labels[api.SeedNodeLabel] == "true" {
+			rackHasSeed = true
+		}
+		rackPods = append(rackPods, pod)
+	}
+
+	if removedPod.Labels[api.SeedNodeLabel] != "true" && rackHasSeed {
+		return nil
+	}
+
+	sort.SliceStable(rackPods, func(i, j int) bool {
+		return podNameLess(rackPods[i].Name, rackPods[j].Name)
+	})
+
+	for _, pod := range rackPods {
+		if pod.Labels[api.SeedNodeLabel] != "true" && isSeedCandidate(dc, pod) {
+			return pod
+		}
+	}
+
+	return nil
+}
+
+// isSeedCandidate returns whether the pod can be labelled as seed, which requires a ready
+// server that is not leaving the ring
+func isSeedCandidate(dc *api.CassandraDatacenter, pod *corev1.Pod) bool {
+	return isServerReady(dc, pod) && pod.Labels[api.CassNodeState] != stateDecommissioning
+}
+
+// podOrdinal returns the StatefulSet ordinal of the pod name, or -1 if it has none
+func podOrdinal(podName string) int {
+	ordinal, err := strconv.Atoi(podName[strings.LastIndex(podName, "-")+1:])
+	if err != nil {
+		return -1
+	}
+	return ordinal
+}
+
+// podNameLess orders pod names by StatefulSet and then by ordinal, so that sts-2 comes
+// before sts-10
+func podNameLess(a, b string) bool {
+	stsA := a[:strings.LastIndex(a, "-")+1]
+	stsB := b[:strings.LastIndex(b, "-")+1]
+	if stsA != stsB {
+		return stsA < stsB
+	}
+	ordinalA, ordinalB := podOrdinal(a), podOrdinal(b)
+	if ordinalA != ordinalB {
+		return ordinalA < ordinalB
+	}
+	return a < b
+}
+
+// rackZone returns the zone a rack is pinned to through its node affinity labels, or an
+// empty string if it is not pinned to a zone
+func rackZone(dc *api.CassandraDatacenter, rackName string) string {
+	labels, _ := rackNodeAffinitylabels(dc, rackName)
+	if zone, ok := labels[zoneLabel]; ok {
+		return zone
+	}
+	return labels["topology.kubernetes.io/zone"]
+}
+
+// selectZoneAwareSeeds returns the names of up to seedCount seed candidates to use as seeds,
+// spreading them over as many zones as possible. Zones are visited in turn, and within a
+// zone the racks are visited in turn, so that a zone outage takes out as few seeds as
+// possible. Racks without a zone are treated as their own zone, which falls back to one
+// seed per rack when no rack is pinned to a zone. When seedsPerRack is set, no rack
+// gets more seeds than that.
+func selectZoneAwareSeeds(dc *api.CassandraDatacenter, pods []*corev1.Pod, seedCount int) []string {
+	zones := []string{}
+	zoneRacks := map[string][]string{}
+	for _, rack := range dc.GetRacks() {
+		zone := rackZone(dc, rack.Name)
+		if zone == "" {
+			zone = "rack:" + rack.Name
+		}
+		if _, ok := zoneRacks[zone]; !ok {
+			zones = append(zones, zone)
+		}
+		zoneRacks[zone] = append(zoneRacks[zone], rack.Name)
+	}
+
+	rackPods := map[string][]string{}
+	for _, pod := range pods {
+		if isSeedCandidate(dc, pod) {
+			rackName := pod.Labels[api.RackLabel]
+			rackPods[rackName] = append(rackPods[rackName], pod.Name)
+		}
+	}
+	for rackName, podNames := range rackPods {
+		sort.SliceStable(podNames, func(i, j int) bool {
+			return podNameLess(podNames[i], podNames[j])
+		})
+		if perRack := int(dc.Spec.SeedsPerRack); perRack > 0 && len(podNames) > perRack {
+			rackPods[rackName] = podNames[:perRack]
+		}
+	}
+
+	// Interleave the pods of the racks in each zone
+	zonePods := map[string][]string{}
+	for _, zone := range zones {
+		for i := 0; ; i++ {
+			added := false
+			for _, rackName := range zoneRacks[zone] {
+				if i < len(rackPods[rackName]) {
+					zonePods[zone] = append(zonePods[zone], rackPods[rackName][i])
+					added = true
+				}
+			}
+			if !added {
+				break
+			}
+		}
+	}
+
+	seeds := []string{}
+	for i := 0; len(seeds) < seedCount; i++ {
+		added := false
+		for _, zone := range zones {
+			if len(seeds) < seedCount && i < len(zonePods[zone]) {
+				seeds = append(seeds, zonePods[zone][i])
+				added = true
+			}
+		}
+		if !added {
+			break
+		}
+	}
+
+	return seeds
+}
+
 // GetStatefulSetForRack returns the statefulset for the rack
 // and whether it currently exists and whether an error occurred
 func (rc *ReconciliationContext) GetStatefulSetForRack(
@@ -1521,9 +1803,9 @@ func (rc *ReconciliationContext) ReconcilePods(statefulSet *appsv1.StatefulSet)
 		podPatch := client.MergeFrom(pod.DeepCopy())
 
 		podLabels := pod.GetLabels()
-		shouldUpdateLabels, updatedLabels := shouldUpdateLabelsForRackResource(podLabels,
-			rc.Datacenter, statefulSet.GetLabels()[api.RackLabel])
-		if shouldUpdateLabels {
+		labelsDelta := rc.Datacenter.GetPodLabelsDelta(statefulSet.GetLabels()[api.RackLabel], podLabels)
+		if len(labelsDelta) > 0 {
+			updatedLabels := utils.MergeMap(map[string]string{}, podLabels, labelsDelta)
 			rc.ReqLogger.Info(
 				"Updating labels",
 				"Pod", podName,
@@ -1577,7 +1859,7 @@ func (rc *ReconciliationContext) ReconcilePods(statefulSet *appsv1.StatefulSet)
 		pvcPatch := client.MergeFrom(pvc.DeepCopy())
 
 		pvcLabels := pvc.GetLabels()
-		shouldUpdateLabels, updatedLabels = shouldUpdateLabelsForRackResource(pvcLabels,
+		shouldUpdateLabels, updatedLabels := shouldUpdateLabelsForRackResource(pvcLabels,
 			rc.Datacenter, statefulSet.GetLabels()[api.RackLabel])
 		if shouldUpdateLabels {
 			rc.ReqLogger.Info("Updating labels",
@@ -1687,9 +1969,9 @@ func (rc *ReconciliationContext) findStartingNodes() (bool, bool, error) {
 
 	for _, pod := range rc.clusterPods {
 		if pod.Labels[api.CassNodeState] == stateStarting {
-			if isServerReady(pod) {
-				rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.StartedCassandra,
-					"Started Cassandra for pod %s", pod.Name)
+			if isServerReady(rc.Datacenter, pod) {
+				rc.Recorder.Event(rc.Datacenter, corev1.EventTypeNormal, events.StartedCassandra,
+					events.RackMessage(rc.Datacenter, pod.Labels[api.RackLabel], "Started Cassandra for pod %s", pod.Name))
 				if err := rc.labelServerPodStarted(pod); err != nil {
 					return false, false, err
 				} else {
@@ -1714,7 +1996,7 @@ func (rc *ReconciliationContext) findStartedNotReadyNodes() (bool, error) {
 	rc.ReqLogger.Info("reconcile_racks::findStartedNotReadyNodes")
 
 	for _, pod := range rc.dcPods {
-		if didServerLoseReadiness(pod) {
+		if didServerLoseReadiness(rc.Datacenter, pod) {
 			if err := rc.labelServerPodStartedNotReady(pod); err != nil {
 				return false, err
 			}
@@ -1722,7 +2004,7 @@ func (rc *ReconciliationContext) findStartedNotReadyNodes() (bool, error) {
 		}
 
 		if isServerStartedNotReady(pod) {
-			if isServerReady(pod) {
+			if isServerReady(rc.Datacenter, pod) {
 				if err := rc.labelServerPodStarted(pod); err != nil {
 					return false, err
 				}
@@ -1733,12 +2015,42 @@ func (rc *ReconciliationContext) findStartedNotReadyNodes() (bool, error) {
 	return false, nil
 }
 
+// checkRestartDataVolume returns an error when the data volume of the pod was created after
+// the node replacements started. A restarted node only starts from what is left on its data
+// volume, so on a new volume it would join the ring as a new node.
+func (rc *ReconciliationContext) checkRestartDataVolume(pod *corev1.Pod) error {
+	timeStartedReplacing, isReplacing := getTimeStartedReplacingNodes(rc.Datacenter)
+	if !isReplacing {
+		return nil
+	}
+
+	pvc, err := rc.GetPodPVC(pod.Namespace, pod.Name)
+	if err != nil {
+		return err
+	}
+
+	timeCreated := pvc.GetCreationTimestamp()
+	if !timeCreated.Before(&timeStartedReplacing) {
+		return fmt.Errorf("Refusing to restart pod %s as its data volume %s is new, use nodeReplacementStrategy %s for nodes which lost their data",
+			pod.Name, pvc.Name, api.NodeReplacementReplace)
+	}
+	return nil
+}
+
 func (rc *ReconciliationContext) startCassandra(endpointData httphelper.CassMetadataEndpoints, pod *corev1.Pod) error {
 	dc := rc.Datacenter
 	mgmtClient := rc.NodeMgmtClient
 
-	// Are we replacing this node?
-	shouldReplacePod := utils.IndexOfString(dc.Status.NodeReplacements, pod.Name) > -1
+	// Are we replacing this node? Nodes restarted instead start under their own identity.
+	shouldReplacePod := utils.IndexOfString(dc.Status.NodeReplacements, pod.Name) > -1 &&
+		dc.GetNodeReplacementStrategy(pod.Name) == api.NodeReplacementReplace
+
+	if utils.IndexOfString(dc.Status.NodeReplacements, pod.Name) > -1 &&
+		dc.GetNodeReplacementStrategy(pod.Name) == api.NodeReplacementRestart {
+		if err := rc.checkRestartDataVolume(pod); err != nil {
+			return err
+		}
+	}
 
 	replaceAddress := ""
 
@@ -1795,7 +2107,7 @@ func (rc *ReconciliationContext) startOneNodePerRack(endpointData httphelper.Cas
 
 	for _, pod := range rc.dcPods {
 		rackName := pod.Labels[api.RackLabel]
-		if isServerReady(pod) {
+		if isServerReady(rc.Datacenter, pod) {
 			rackReadyCount[rackName]++
 		}
 	}
@@ -1811,7 +2123,7 @@ func (rc *ReconciliationContext) startOneNodePerRack(endpointData httphelper.Cas
 		}
 		rackThatNeedsNode = rackName
 		for _, pod := range rc.dcPods {
-			mgmtApiUp := isMgmtApiRunning(pod)
+			mgmtApiUp := isMgmtApiRunning(rc.Datacenter, pod)
 			if !isServerReadyToStart(pod) || !mgmtApiUp {
 				continue
 			}
@@ -1847,7 +2159,7 @@ func (rc *ReconciliationContext) startAllNodes(endpointData httphelper.CassMetad
 	rc.ReqLogger.Info("reconcile_racks::startAllNodes")
 
 	for _, pod := range rc.dcPods {
-		if isMgmtApiRunning(pod) && !isServerReady(pod) && !isServerStarted(pod) {
+		if isMgmtApiRunning(rc.Datacenter, pod) && !isServerReady(rc.Datacenter, pod) && !isServerStarted(pod) {
 			if err := rc.startCassandra(endpointData, pod); err != nil {
 				return false, err
 			}
@@ -1858,7 +2170,7 @@ func (rc *ReconciliationContext) startAllNodes(endpointData httphelper.CassMetad
 	// this extra pass only does anything when we have a combination of
 	// ready server pods and pods that are not running - possibly stuck pending
 	for _, pod := range rc.dcPods {
-		if !isMgmtApiRunning(pod) {
+		if !isMgmtApiRunning(rc.Datacenter, pod) {
 			rc.ReqLogger.Info(
 				"management api is not running on pod",
 				"pod", pod.Name,
@@ -1874,7 +2186,7 @@ func (rc *ReconciliationContext) countReadyAndStarted() (int, int) {
 	ready := 0
 	started := 0
 	for _, pod := range rc.dcPods {
-		if isServerReady(pod) {
+		if isServerReady(rc.Datacenter, pod) {
 			ready++
 			rc.ReqLogger.Info(
 				"found a ready pod",
@@ -1895,15 +2207,9 @@ func (rc *ReconciliationContext) countReadyAndStarted() (int, int) {
 	return ready, started
 }
 
-func isMgmtApiRunning(pod *corev1.Pod) bool {
-	podStatus := pod.Status
-	statuses := podStatus.ContainerStatuses
-	for _, status := range statuses {
-		if status.Name != "cassandra" {
-			continue
-		}
-		state := status.State
-		runInfo := state.Running
+func isMgmtApiRunning(dc *api.CassandraDatacenter, pod *corev1.Pod) bool {
+	if status := getMgmtApiContainerStatus(dc, pod); status != nil {
+		runInfo := status.State.Running
 		if runInfo != nil {
 			// give management API ten seconds to come up
 			tenSecondsAgo := time.Now().Add(time.Second * -10)
@@ -1934,20 +2240,15 @@ func isServerReadyToStart(pod *corev1.Pod) bool {
 	return pod.Labels[api.CassNodeState] == stateReadyToStart
 }
 
-func didServerLoseReadiness(pod *corev1.Pod) bool {
+func didServerLoseReadiness(dc *api.CassandraDatacenter, pod *corev1.Pod) bool {
 	if pod.Labels[api.CassNodeState] == stateStarted {
-		return !isServerReady(pod)
+		return !isServerReady(dc, pod)
 	}
 	return false
 }
 
-func isServerReady(pod *corev1.Pod) bool {
-	status := pod.Status
-	statuses := status.ContainerStatuses
-	for _, status := range statuses {
-		if status.Name != "cassandra" {
-			continue
-		}
+func isServerReady(dc *api.CassandraDatacenter, pod *corev1.Pod) bool {
+	if status := getMgmtApiContainerStatus(dc, pod); status != nil {
 		return status.Ready
 	}
 	return false
@@ -2013,10 +2314,8 @@ func (rc *ReconciliationContext) CheckRollingRestart() result.ReconcileResult {
 		}
 	}
 
-	cutoff := &dc.Status.LastRollingRestart
 	for _, pod := range rc.dcPods {
-		podStartTime := pod.GetCreationTimestamp()
-		if podStartTime.Before(cutoff) {
+		if dc.PodNeedsRollingRestart(pod) {
 			rc.Recorder.Eventf(rc.Datacenter, corev1.EventTypeNormal, events.RestartingCassandra,
 				"Restarting Cassandra for pod %s", pod.Name)
 
@@ -2079,16 +2378,49 @@ func (rc *ReconciliationContext) CheckConditionInitializedAndReady() result.Reco
 	return result.Continue()
 }
 
-func (rc *ReconciliationContext) cleanupAfterScaling() error {
-	var err error
+// getCleanupAfterScalingPods returns the pods on which a keyspace cleanup of all keyspaces
+// runs through the management API once a scale up completes, in the order the cleanups
+// run. These are all started pods, as every node may have lost token ranges to the new
+// ones.
+func getCleanupAfterScalingPods(pods []*corev1.Pod) []*corev1.Pod {
+	cleanupPods := []*corev1.Pod{}
+	for _, pod := range pods {
+		if isServerStarted(pod) {
+			cleanupPods = append(cleanupPods, pod)
+		}
+	}
+	sort.SliceStable(cleanupPods, func(i, j int) bool {
+		return cleanupPods[i].Name < cleanupPods[j].Name
+	})
+	return cleanupPods
+}
 
-	for idx := range rc.dcPods {
-		err = rc.NodeMgmtClient.CallKeyspaceCleanupEndpoint(rc.dcPods[idx], -1, "", nil)
-		if err == nil {
-			break
+// cleanupAfterScaling runs a keyspace cleanup on the next node which was not cleaned up
+// since the scale up started, and records it in the CleanedUpAfterScalingAnnotation of its
+// pod. Only one node is cleaned up per reconcile, as every cleanup rewrites the sstables of
+// its node. It returns whether all nodes are cleaned up.
+func (rc *ReconciliationContext) cleanupAfterScaling() (bool, error) {
+	scalingUp, _ := rc.Datacenter.GetCondition(api.DatacenterScalingUp)
+	scaleUpStart := scalingUp.LastTransitionTime.UTC().Format(time.RFC3339)
+
+	for _, pod := range getCleanupAfterScalingPods(rc.dcPods) {
+		if pod.Annotations[api.CleanedUpAfterScalingAnnotation] == scaleUpStart {
+			continue
+		}
+
+		rc.ReqLogger.Info("Running keyspace cleanup after scaling up", "pod", pod.Name)
+		if err := rc.NodeMgmtClient.CallKeyspaceCleanupEndpoint(pod, -1, "", nil); err != nil {
+			return false, err
+		}
+
+		patch := client.MergeFrom(pod.DeepCopy())
+		metav1.SetMetaDataAnnotation(&pod.ObjectMeta, api.CleanedUpAfterScalingAnnotation, scaleUpStart)
+		if err := rc.Client.Patch(rc.Ctx, pod, patch); err != nil {
+			return false, err
 		}
+		return false, nil
 	}
-	return err
+	return true, nil
 }
 
 func (rc *ReconciliationContext) CheckCassandraNodeStatuses() result.ReconcileResult {
@@ -2126,12 +2458,17 @@ func (rc *ReconciliationContext) CheckClearActionConditions() result.ReconcileRe
 	}
 	updated := false
 
-	// Explicitly handle scaling up here because we want to run a cleanup afterwards
+	// Explicitly handle scaling up here because we may want to run a cleanup afterwards
 	if dc.GetConditionStatus(api.DatacenterScalingUp) == corev1.ConditionTrue {
-		err := rc.cleanupAfterScaling()
-		if err != nil {
-			logger.Error(err, "error cleaning up after scaling datacenter")
-			return result.Error(err)
+		if dc.Spec.AutoCleanupAfterScaleUp {
+			cleanedUp, err := rc.cleanupAfterScaling()
+			if err != nil {
+				logger.Error(err, "error cleaning up after scaling datacenter")
+				return result.Error(err)
+			}
+			if !cleanedUp {
+				return result.RequeueSoon(2)
+			}
 		}
 
 		updated = rc.setCondition(
@@ -2363,5 +2700,9 @@ func (rc *ReconciliationContext) ReconcileAllRacks() (reconcile.Result, error) {
 
 	rc.ReqLogger.Info("All StatefulSets should now be reconciled.")
 
+	if interval := rc.Datacenter.GetReconcileInterval(); interval > 0 {
+		return reconcile.Result{Requeue: true, RequeueAfter: interval}, nil
+	}
+
 	return result.Done().Output()
 }
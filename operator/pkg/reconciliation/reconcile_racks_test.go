@@ -9,11 +9,14 @@ import (
 	"io/ioutil"
 	"net/http"
 	"reflect"
+	"sort"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/k8ssandra/cass-operator/operator/internal/result"
 	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/operator/pkg/dynamicwatch"
 	"github.com/k8ssandra/cass-operator/operator/pkg/httphelper"
 	"github.com/k8ssandra/cass-operator/operator/pkg/mocks"
 	"github.com/k8ssandra/cass-operator/operator/pkg/oplabels"
@@ -26,6 +29,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
@@ -286,6 +290,42 @@ func TestCheckRackPodTemplate_CanaryUpgrade(t *testing.T) {
 	assert.True(t, result.Completed())
 }
 
+func TestCheckRackPodTemplate_CanaryUpgradeResumed(t *testing.T) {
+	rc, _, cleanpMockSrc := setupTest()
+	defer cleanpMockSrc()
+
+	rc.Datacenter.Spec.ServerVersion = "6.8.2"
+	rc.Datacenter.Spec.Racks = []api.Rack{
+		{Name: "rack1", Zone: "zone-1"},
+	}
+
+	if err := rc.CalculateRackInformation(); err != nil {
+		t.Fatalf("failed to calculate rack information: %s", err)
+	}
+
+	result := rc.CheckRackCreation()
+	assert.False(t, result.Completed(), "CheckRackCreation did not complete as expected")
+
+	rc.Datacenter.Spec.CanaryUpgrade = true
+	rc.Datacenter.Spec.CanaryUpgradeCount = 1
+	rc.Datacenter.Spec.ServerVersion = "6.8.3"
+
+	result = rc.CheckRackPodTemplate()
+	assert.True(t, result.Completed())
+	assert.Equal(t, int32(1), getStatefulSetPartition(rc.statefulSets[0]))
+
+	// Turning the canary upgrade off resets the partition, although the pod template
+	// is unchanged
+	rc.Datacenter.Spec.CanaryUpgrade = false
+
+	result = rc.CheckRackPodTemplate()
+	assert.True(t, result.Completed())
+	assert.Equal(t, int32(0), getStatefulSetPartition(rc.statefulSets[0]))
+
+	result = rc.CheckRackPodTemplate()
+	assert.False(t, result.Completed())
+}
+
 func TestReconcilePods(t *testing.T) {
 	t.Skip()
 	rc, _, cleanupMockScr := setupTest()
@@ -498,6 +538,26 @@ func TestCalculateRackInformation_MultiRack(t *testing.T) {
 	// TODO add more RackInformation validation
 }
 
+func TestCalculateRackInformation_SeedsPerRack(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.Racks = []api.Rack{{
+		Name: "rack0",
+	}, {
+		Name: "rack1",
+	}}
+	rc.Datacenter.Spec.Size = 5
+	rc.Datacenter.Spec.SeedsPerRack = 3
+
+	err := rc.CalculateRackInformation()
+	assert.NoErrorf(t, err, "Should not have returned an error")
+
+	// rack1 only has two nodes
+	assert.Equal(t, 3, rc.desiredRackInformation[0].SeedCount)
+	assert.Equal(t, 2, rc.desiredRackInformation[1].SeedCount)
+}
+
 func TestReconcileRacks(t *testing.T) {
 	rc, _, cleanupMockScr := setupTest()
 	defer cleanupMockScr()
@@ -1126,7 +1186,7 @@ func Test_isServerReady(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := isServerReady(tt.args.pod); got != tt.want {
+			if got := isServerReady(&api.CassandraDatacenter{}, tt.args.pod); got != tt.want {
 				t.Errorf("isServerReady() = %v, want %v", got, tt.want)
 			}
 		})
@@ -1177,13 +1237,43 @@ func Test_isMgmtApiRunning(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := isMgmtApiRunning(tt.args.pod); got != tt.want {
+			if got := isMgmtApiRunning(&api.CassandraDatacenter{}, tt.args.pod); got != tt.want {
 				t.Errorf("isMgmtApiRunning() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
 
+func Test_isServerReady_sidecarManagementApi(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			ManagementApiMode: api.ManagementApiModeSidecar,
+		},
+	}
+
+	started := metav1.Date(2019, time.July, 4, 12, 12, 12, 0, time.UTC)
+	pod := makeMockReadyStartedPod()
+	pod.Status.ContainerStatuses[0].State.Running = &corev1.ContainerStateRunning{StartedAt: started}
+	pod.Status.ContainerStatuses = append(pod.Status.ContainerStatuses, corev1.ContainerStatus{
+		Name:  api.ManagementApiContainerName,
+		Ready: false,
+	})
+
+	// The server container is running and ready, but the sidecar is not
+	assert.False(t, isServerReady(dc, pod))
+	assert.False(t, isMgmtApiRunning(dc, pod))
+
+	pod.Status.ContainerStatuses[1].Ready = true
+	pod.Status.ContainerStatuses[1].State.Running = &corev1.ContainerStateRunning{StartedAt: started}
+	assert.True(t, isServerReady(dc, pod))
+	assert.True(t, isMgmtApiRunning(dc, pod))
+
+	pod.Status.ContainerStatuses = pod.Status.ContainerStatuses[:1]
+	assert.False(t, isServerReady(dc, pod))
+	assert.False(t, isMgmtApiRunning(dc, pod))
+	assert.True(t, isServerReady(&api.CassandraDatacenter{}, pod))
+}
+
 func Test_shouldUpdateLabelsForRackResource(t *testing.T) {
 	clusterName := "cassandradatacenter-example-cluster"
 	dcName := "cassandradatacenter-example"
@@ -1437,3 +1527,390 @@ func Test_callPodEndpoint_RequestFail(t *testing.T) {
 		assert.Fail(t, "Should have returned error")
 	}
 }
+
+func Test_findSeedReplacementPod(t *testing.T) {
+	dc := &api.CassandraDatacenter{}
+	makePod := func(name, rack string, seed bool, ready bool) *corev1.Pod {
+		pod := makeMockReadyStartedPod()
+		pod.Name = name
+		pod.Labels[api.RackLabel] = rack
+		if seed {
+			pod.Labels[api.SeedNodeLabel] = "true"
+		}
+		pod.Status.ContainerStatuses[0].Ready = ready
+		return pod
+	}
+
+	r1Seed := makePod("r1-0", "r1", true, true)
+	r1NotReady := makePod("r1-1", "r1", false, false)
+	r1Ready := makePod("r1-2", "r1", false, true)
+	r2Seed := makePod("r2-0", "r2", true, true)
+	r2Other := makePod("r2-1", "r2", false, true)
+	pods := []*corev1.Pod{r2Other, r1Ready, r2Seed, r1NotReady, r1Seed}
+
+	// Removing a seed promotes the first ready pod of the same rack
+	assert.Equal(t, r1Ready, findSeedReplacementPod(dc, pods, r1Seed))
+	assert.Equal(t, r2Other, findSeedReplacementPod(dc, pods, r2Seed))
+
+	// Removing a non-seed from a rack which keeps its seed needs no promotion
+	assert.Nil(t, findSeedReplacementPod(dc, pods, r1Ready))
+
+	// A rack which has lost its seed gets one back
+	r1Ready.Labels[api.SeedNodeLabel] = ""
+	assert.Nil(t, findSeedReplacementPod(dc, []*corev1.Pod{r1NotReady}, r1Ready))
+	assert.Equal(t, r1Ready, findSeedReplacementPod(dc, []*corev1.Pod{r1NotReady, r1Ready}, r1NotReady))
+
+	// Pods are ordered by StatefulSet ordinal rather than by name, and pods leaving the
+	// ring are never promoted
+	r1Ten := makePod("r1-10", "r1", false, true)
+	r1Two := makePod("r1-2", "r1", false, true)
+	assert.Equal(t, r1Two, findSeedReplacementPod(dc, []*corev1.Pod{r1Ten, r1Two}, r1Seed))
+	r1Two.Labels[api.CassNodeState] = stateDecommissioning
+	assert.Equal(t, r1Ten, findSeedReplacementPod(dc, []*corev1.Pod{r1Ten, r1Two}, r1Seed))
+}
+
+func Test_podNameLess(t *testing.T) {
+	names := []string{"c-dc-r2-sts-0", "c-dc-r1-sts-10", "c-dc-r1-sts-2", "c-dc-r1-sts-0"}
+	sort.SliceStable(names, func(i, j int) bool {
+		return podNameLess(names[i], names[j])
+	})
+	assert.Equal(t, []string{"c-dc-r1-sts-0", "c-dc-r1-sts-2", "c-dc-r1-sts-10", "c-dc-r2-sts-0"}, names)
+}
+
+func TestReplaceLeavingSeed(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	makePod := func(name string, seed bool) *corev1.Pod {
+		pod := makeMockReadyStartedPod()
+		pod.Name = name
+		pod.Namespace = rc.Datacenter.Namespace
+		pod.Labels[api.RackLabel] = "r1"
+		if seed {
+			pod.Labels[api.SeedNodeLabel] = "true"
+		}
+		assert.NoError(t, rc.Client.Create(rc.Ctx, pod))
+		return pod
+	}
+
+	leaving := makePod("r1-sts-2", true)
+	other := makePod("r1-sts-1", false)
+	rc.dcPods = []*corev1.Pod{leaving, other}
+
+	assert.NoError(t, rc.replaceLeavingSeed(leaving))
+
+	updated := &corev1.Pod{}
+	assert.NoError(t, rc.Client.Get(rc.Ctx, types.NamespacedName{Name: other.Name, Namespace: other.Namespace}, updated))
+	assert.Equal(t, "true", updated.Labels[api.SeedNodeLabel])
+}
+
+func Test_selectZoneAwareSeeds(t *testing.T) {
+	makePod := func(name, rack string) *corev1.Pod {
+		pod := makeMockReadyStartedPod()
+		pod.Name = name
+		pod.Labels[api.RackLabel] = rack
+		return pod
+	}
+
+	pods := []*corev1.Pod{
+		makePod("r1-0", "r1"), makePod("r1-1", "r1"),
+		makePod("r2-0", "r2"), makePod("r2-1", "r2"),
+		makePod("r3-0", "r3"), makePod("r3-1", "r3"),
+	}
+	notReady := makePod("r3-2", "r3")
+	notReady.Status.ContainerStatuses[0].Ready = false
+	pods = append(pods, notReady)
+
+	dc := &api.CassandraDatacenter{
+		Spec: api.CassandraDatacenterSpec{
+			Racks: []api.Rack{
+				{Name: "r1", Zone: "zone-a"},
+				{Name: "r2", NodeAffinityLabels: map[string]string{zoneLabel: "zone-a"}},
+				{Name: "r3", Zone: "zone-b"},
+			},
+		},
+	}
+
+	// Three racks over two zones, both zones get a seed before a zone gets a second one
+	assert.Equal(t, []string{"r1-0", "r3-0"}, selectZoneAwareSeeds(dc, pods, 2))
+	assert.Equal(t, []string{"r1-0", "r3-0", "r2-0"}, selectZoneAwareSeeds(dc, pods, 3))
+	assert.Equal(t, []string{"r1-0", "r3-0", "r2-0", "r3-1", "r1-1", "r2-1"}, selectZoneAwareSeeds(dc, pods, 10))
+
+	// Without zones there is one seed per rack
+	for i := range dc.Spec.Racks {
+		dc.Spec.Racks[i].Zone = ""
+		dc.Spec.Racks[i].NodeAffinityLabels = nil
+	}
+	assert.Equal(t, []string{"r1-0", "r2-0", "r3-0"}, selectZoneAwareSeeds(dc, pods, 3))
+
+	// No rack gets more than seedsPerRack seeds
+	dc.Spec.SeedsPerRack = 1
+	assert.Equal(t, []string{"r1-0", "r2-0", "r3-0"}, selectZoneAwareSeeds(dc, pods, 10))
+}
+
+func TestCheckSeedLabels_ZoneAware(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Spec.Racks = []api.Rack{
+		{Name: "r1", Zone: "zone-a"},
+		{Name: "r2", Zone: "zone-a"},
+		{Name: "r3", Zone: "zone-b"},
+	}
+	rc.desiredRackInformation = []*RackInformation{
+		{RackName: "r1", NodeCount: 2, SeedCount: 1},
+		{RackName: "r2", NodeCount: 2, SeedCount: 1},
+		{RackName: "r3", NodeCount: 2, SeedCount: 0},
+	}
+
+	rc.dcPods = []*corev1.Pod{}
+	for _, rack := range rc.Datacenter.Spec.Racks {
+		for ordinal := 0; ordinal < 2; ordinal++ {
+			pod := makeMockReadyStartedPod()
+			pod.Name = fmt.Sprintf("%s-sts-%d", rack.Name, ordinal)
+			pod.Namespace = rc.Datacenter.Namespace
+			for key, value := range rc.Datacenter.GetRackLabels(rack.Name) {
+				pod.Labels[key] = value
+			}
+			assert.NoError(t, rc.Client.Create(rc.Ctx, pod))
+			rc.dcPods = append(rc.dcPods, pod)
+		}
+	}
+
+	seedCount, err := rc.checkSeedLabels()
+	assert.NoError(t, err)
+	assert.Equal(t, 2, seedCount)
+
+	seeds := []string{}
+	for _, pod := range rc.dcPods {
+		if pod.Labels[api.SeedNodeLabel] == "true" {
+			seeds = append(seeds, pod.Name)
+		}
+	}
+	// The seeds are spread over both zones rather than following the per rack counts
+	assert.Equal(t, []string{"r1-sts-0", "r3-sts-0"}, seeds)
+}
+
+func Test_getCleanupAfterScalingPods(t *testing.T) {
+	makePod := func(name string, state string) *corev1.Pod {
+		pod := makeMockReadyStartedPod()
+		pod.Name = name
+		pod.Labels[api.CassNodeState] = state
+		return pod
+	}
+
+	started := makePod("r1-1", stateStarted)
+	startedNotReady := makePod("r1-0", stateStartedNotReady)
+	readyToStart := makePod("r2-0", stateReadyToStart)
+
+	pods := getCleanupAfterScalingPods([]*corev1.Pod{started, readyToStart, startedNotReady})
+	assert.Equal(t, []*corev1.Pod{startedNotReady, started}, pods)
+}
+
+func TestCleanupAfterScaling(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.SetCondition(api.DatacenterCondition{
+		Type:               api.DatacenterScalingUp,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Unix(1600000000, 0),
+	})
+
+	rc.dcPods = nil
+	for _, name := range []string{"r1-1", "r1-0"} {
+		pod := makeMockReadyStartedPod()
+		pod.Name = name
+		pod.Namespace = rc.Datacenter.Namespace
+		pod.Status.PodIP = "1.2.3.4"
+		assert.NoError(t, rc.Client.Create(rc.Ctx, pod))
+		rc.dcPods = append(rc.dcPods, pod)
+	}
+
+	mockHttpClient := &mocks.HttpClient{}
+	mockHttpClient.On("Do",
+		mock.MatchedBy(
+			func(req *http.Request) bool {
+				return req != nil
+			})).
+		Return(func(*http.Request) *http.Response {
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader("OK")),
+			}
+		}, nil)
+
+	rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+		Client:   mockHttpClient,
+		Log:      rc.ReqLogger,
+		Protocol: "http",
+	}
+
+	cleanedUp, err := rc.cleanupAfterScaling()
+	assert.NoError(t, err)
+	assert.False(t, cleanedUp)
+	mockHttpClient.AssertNumberOfCalls(t, "Do", 1)
+	assert.Equal(t, "2020-09-13T12:26:40Z", rc.dcPods[1].Annotations[api.CleanedUpAfterScalingAnnotation])
+	assert.Empty(t, rc.dcPods[0].Annotations[api.CleanedUpAfterScalingAnnotation])
+
+	cleanedUp, err = rc.cleanupAfterScaling()
+	assert.NoError(t, err)
+	assert.False(t, cleanedUp)
+	mockHttpClient.AssertNumberOfCalls(t, "Do", 2)
+	assert.Equal(t, "2020-09-13T12:26:40Z", rc.dcPods[0].Annotations[api.CleanedUpAfterScalingAnnotation])
+
+	cleanedUp, err = rc.cleanupAfterScaling()
+	assert.NoError(t, err)
+	assert.True(t, cleanedUp)
+	mockHttpClient.AssertNumberOfCalls(t, "Do", 2)
+
+	// A later scale up cleans up every node again
+	rc.Datacenter.SetCondition(api.DatacenterCondition{
+		Type:               api.DatacenterScalingUp,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Unix(1700000000, 0),
+	})
+	cleanedUp, err = rc.cleanupAfterScaling()
+	assert.NoError(t, err)
+	assert.False(t, cleanedUp)
+	mockHttpClient.AssertNumberOfCalls(t, "Do", 3)
+}
+
+func TestCreateUsers_UpsertsSuperuser(t *testing.T) {
+	// The superuser is upserted even when its secret is unchanged, as the cluster may have
+	// lost it, e.g. when a datacenter is recreated over existing status
+	tests := []struct {
+		name      string
+		upserted  metav1.Time
+		wantCalls int
+	}{
+		{
+			name:      "never upserted",
+			upserted:  metav1.Unix(1, 0),
+			wantCalls: 2,
+		},
+		{
+			name:      "unchanged since the upsert",
+			upserted:  metav1.NewTime(time.Now().Add(time.Hour)),
+			wantCalls: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc, _, cleanupMockScr := setupTest()
+			defer cleanupMockScr()
+
+			rc.SecretWatches = dynamicwatch.NewDynamicSecretWatches(rc.Client)
+			rc.Recorder = record.NewFakeRecorder(100)
+			rc.Datacenter.Spec.Users = []api.CassandraUser{{SecretName: "app-user"}}
+			rc.Datacenter.Status.SuperUserUpserted = tt.upserted
+
+			superuserName := rc.Datacenter.GetSuperuserSecretNamespacedName()
+			for _, name := range []string{superuserName.Name, "app-user"} {
+				secret := &corev1.Secret{
+					ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: rc.Datacenter.Namespace},
+					Data: map[string][]byte{
+						api.SuperuserSecretUsernameKey: []byte(name),
+						api.SuperuserSecretPasswordKey: []byte("secret"),
+					},
+				}
+				assert.NoError(t, rc.Client.Create(rc.Ctx, secret))
+			}
+
+			mockHttpClient := &mocks.HttpClient{}
+			mockHttpClient.On("Do",
+				mock.MatchedBy(
+					func(req *http.Request) bool {
+						return req != nil
+					})).
+				Return(func(*http.Request) *http.Response {
+					return &http.Response{
+						StatusCode: http.StatusOK,
+						Body:       ioutil.NopCloser(strings.NewReader("OK")),
+					}
+				}, nil)
+
+			rc.NodeMgmtClient = httphelper.NodeMgmtClient{
+				Client:   mockHttpClient,
+				Log:      rc.ReqLogger,
+				Protocol: "http",
+			}
+
+			pod := makeMockReadyStartedPod()
+			pod.Status.PodIP = "1.2.3.4"
+			rc.dcPods = []*corev1.Pod{pod}
+
+			r := rc.CreateUsers()
+			assert.Equal(t, result.Continue(), r)
+			mockHttpClient.AssertNumberOfCalls(t, "Do", tt.wantCalls)
+		})
+	}
+}
+
+func TestCheckRestartDataVolume(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.SetCondition(api.DatacenterCondition{
+		Type:               api.DatacenterReplacingNodes,
+		Status:             corev1.ConditionTrue,
+		LastTransitionTime: metav1.Unix(1600000000, 0),
+	})
+
+	pod := makeMockReadyStartedPod()
+	pod.Name = "r1-0"
+	pod.Namespace = rc.Datacenter.Namespace
+
+	pvc := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              PvcName + "-" + pod.Name,
+			Namespace:         pod.Namespace,
+			CreationTimestamp: metav1.Unix(1500000000, 0),
+		},
+	}
+	assert.NoError(t, rc.Client.Create(rc.Ctx, pvc))
+	assert.NoError(t, rc.checkRestartDataVolume(pod))
+
+	pvc.CreationTimestamp = metav1.Unix(1600000001, 0)
+	assert.NoError(t, rc.Client.Update(rc.Ctx, pvc))
+	assert.EqualError(t, rc.checkRestartDataVolume(pod),
+		"Refusing to restart pod r1-0 as its data volume server-data-r1-0 is new, use nodeReplacementStrategy Replace for nodes which lost their data")
+}
+
+func Test_redactConfigBuilderInput(t *testing.T) {
+	config := `{"cassandra-yaml":{"num_tokens":16,"server_encryption_options":{"keystore":"/etc/keystore","keystore_password":"hunter2"}},"ldap":[{"bind_secret":"s3cr3t"}]}`
+
+	redacted, err := redactConfigBuilderInput(config)
+	assert.NoError(t, err)
+	assert.NotContains(t, redacted, "hunter2")
+	assert.NotContains(t, redacted, "s3cr3t")
+	assert.Contains(t, redacted, `"keystore_password": "<redacted>"`)
+	assert.Contains(t, redacted, `"keystore": "/etc/keystore"`)
+	assert.Contains(t, redacted, `"num_tokens": 16`)
+
+	_, err = redactConfigBuilderInput("{")
+	assert.Error(t, err)
+}
+
+func Test_shouldLogConfigBuilderInput(t *testing.T) {
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc1", Namespace: "ns-log-config"},
+	}
+	other := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{Name: "dc2", Namespace: "ns-log-config"},
+	}
+
+	assert.True(t, shouldLogConfigBuilderInput(dc, "hash1"))
+	assert.False(t, shouldLogConfigBuilderInput(dc, "hash1"))
+	assert.True(t, shouldLogConfigBuilderInput(other, "hash1"))
+	assert.True(t, shouldLogConfigBuilderInput(dc, "hash2"))
+	assert.False(t, shouldLogConfigBuilderInput(dc, "hash2"))
+
+	// A datacenter recreated after its deletion logs its input again
+	forgetConfigBuilderInput(dc)
+	_, ok := loggedConfigBuilderInputs.Load("ns-log-config/dc1")
+	assert.False(t, ok)
+	assert.True(t, shouldLogConfigBuilderInput(dc, "hash2"))
+	assert.False(t, shouldLogConfigBuilderInput(other, "hash1"))
+}
@@ -9,6 +9,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/k8ssandra/cass-operator/operator/pkg/utils"
 )
@@ -44,6 +45,30 @@ func (rc *ReconciliationContext) CreateHeadlessServices() result.ReconcileResult
 	return result.Continue()
 }
 
+// seedServicePublishNotReadyAddresses returns whether the seed service, which the
+// datacenters of the cluster share, publishes not-ready addresses. It does so as long as
+// any of the datacenters needs it.
+func (rc *ReconciliationContext) seedServicePublishNotReadyAddresses() (bool, error) {
+	dc := rc.Datacenter
+	if dc.GetSeedServicePublishNotReadyAddresses() {
+		return true, nil
+	}
+
+	dcs := &api.CassandraDatacenterList{}
+	if err := rc.Client.List(rc.Ctx, dcs, client.InNamespace(dc.Namespace)); err != nil {
+		return false, err
+	}
+	for _, other := range dcs.Items {
+		if other.Name == dc.Name || !dc.IsSameCluster(&other) {
+			continue
+		}
+		if other.GetSeedServicePublishNotReadyAddresses() {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 // ReconcileHeadlessService ...
 func (rc *ReconciliationContext) CheckHeadlessServices() result.ReconcileResult {
 	// unpacking
@@ -55,8 +80,16 @@ func (rc *ReconciliationContext) CheckHeadlessServices() result.ReconcileResult
 
 	// Check if there is a headless service for the cluster
 
+	publishNotReadySeeds, err := rc.seedServicePublishNotReadyAddresses()
+	if err != nil {
+		logger.Error(err, "Could not list the datacenters sharing the seed service")
+		return result.Error(err)
+	}
+
 	cqlService := newServiceForCassandraDatacenter(dc)
-	seedService := newSeedServiceForCassandraDatacenter(dc)
+	seedService := newSeedServiceForCassandraDatacenter(dc, publishNotReadySeeds)
+	// The all-pods service also governs the statefulsets, see GetStatefulSetServiceName,
+	// so it is always needed for the pod DNS names
 	allPodsService := newAllPodsServiceForCassandraDatacenter(dc)
 
 	services := []*corev1.Service{cqlService, seedService, allPodsService}
@@ -71,6 +104,15 @@ func (rc *ReconciliationContext) CheckHeadlessServices() result.ReconcileResult
 		services = append(services, nodePortService)
 	}
 
+	if dc.Spec.ManagementApiService {
+		mgmtApiService, err := newManagementApiServiceForCassandraDatacenter(dc)
+		if err != nil {
+			logger.Error(err, "Could not build the management API service")
+			return result.Error(err)
+		}
+		services = append(services, mgmtApiService)
+	}
+
 	createNeeded := []*corev1.Service{}
 
 	for idx := range services {
@@ -11,6 +11,7 @@ import (
 	"github.com/stretchr/testify/mock"
 	corev1 "k8s.io/api/core/v1"
 
+	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
 	"github.com/k8ssandra/cass-operator/operator/pkg/mocks"
 )
 
@@ -106,3 +107,30 @@ func TestCreateHeadlessService_ClientReturnsError(t *testing.T) {
 
 	mockClient.AssertExpectations(t)
 }
+
+func TestSeedServicePublishNotReadyAddresses(t *testing.T) {
+	rc, _, cleanupMockScr := setupTest()
+	defer cleanupMockScr()
+
+	rc.Datacenter.Status.CassandraOperatorProgress = api.ProgressReady
+	publish, err := rc.seedServicePublishNotReadyAddresses()
+	assert.NoError(t, err)
+	assert.False(t, publish, "a ready datacenter alone should not publish not-ready seeds")
+
+	otherDc := rc.Datacenter.DeepCopy()
+	otherDc.Name = "other-dc"
+	otherDc.ResourceVersion = ""
+	otherDc.Status.CassandraOperatorProgress = api.ProgressUpdating
+	assert.NoError(t, rc.Client.Create(rc.Ctx, otherDc))
+
+	publish, err = rc.seedServicePublishNotReadyAddresses()
+	assert.NoError(t, err)
+	assert.True(t, publish, "a forming datacenter of the same cluster should publish not-ready seeds")
+
+	otherDc.Spec.ClusterName = "other-cluster"
+	assert.NoError(t, rc.Client.Update(rc.Ctx, otherDc))
+
+	publish, err = rc.seedServicePublishNotReadyAddresses()
+	assert.NoError(t, err)
+	assert.False(t, publish, "datacenters of other clusters should not matter")
+}
@@ -0,0 +1,97 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package reconciliation
+
+import (
+	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// GetExpectedResourceUsage returns what the datacenter counts against a namespace
+// ResourceQuota once all its pods and persistent volume claims exist, keyed by the quota
+// resource names. The pods are the ones of the statefulsets the operator would create, so
+// per-rack resources, the sidecar and init containers and the pod template are accounted
+// for.
+func GetExpectedResourceUsage(dc *api.CassandraDatacenter) (corev1.ResourceList, error) {
+	usage := corev1.ResourceList{}
+
+	for _, expected := range dc.GetExpectedStatefulSets() {
+		if expected.NodeCount == 0 {
+			continue
+		}
+
+		sts, err := newStatefulSetForCassandraDatacenter(nil, expected.RackName, dc, expected.NodeCount, false)
+		if err != nil {
+			return nil, err
+		}
+
+		podUsage := getPodResourceUsage(&sts.Spec.Template.Spec)
+		podUsage[corev1.ResourcePods] = *resource.NewQuantity(1, resource.DecimalSI)
+		for _, claim := range sts.Spec.VolumeClaimTemplates {
+			addResourceList(podUsage, corev1.ResourceList{
+				corev1.ResourceRequestsStorage:        claim.Spec.Resources.Requests[corev1.ResourceStorage],
+				corev1.ResourcePersistentVolumeClaims: *resource.NewQuantity(1, resource.DecimalSI),
+			})
+		}
+
+		for i := 0; i < expected.NodeCount; i++ {
+			addResourceList(usage, podUsage)
+		}
+	}
+
+	return usage, nil
+}
+
+// getPodResourceUsage returns the cpu and memory requests and limits a pod counts against
+// a ResourceQuota, which is the larger of the sum over its containers and the largest init
+// container, as init containers run one at a time before the other containers
+func getPodResourceUsage(podSpec *corev1.PodSpec) corev1.ResourceList {
+	containers := corev1.ResourceList{}
+	for _, container := range podSpec.Containers {
+		addResourceList(containers, getContainerResourceUsage(container))
+	}
+
+	for _, container := range podSpec.InitContainers {
+		for name, quantity := range getContainerResourceUsage(container) {
+			if current, ok := containers[name]; !ok || quantity.Cmp(current) > 0 {
+				containers[name] = quantity
+			}
+		}
+	}
+
+	return containers
+}
+
+// getContainerResourceUsage returns the cpu and memory requests and limits of a container
+// as quota resource names. A missing request defaults to the limit, as it does in the pod.
+func getContainerResourceUsage(container corev1.Container) corev1.ResourceList {
+	usage := corev1.ResourceList{}
+	for name, quotaNames := range map[corev1.ResourceName][2]corev1.ResourceName{
+		corev1.ResourceCPU:    {corev1.ResourceRequestsCPU, corev1.ResourceLimitsCPU},
+		corev1.ResourceMemory: {corev1.ResourceRequestsMemory, corev1.ResourceLimitsMemory},
+	} {
+		limit, hasLimit := container.Resources.Limits[name]
+		if hasLimit {
+			usage[quotaNames[1]] = limit
+		}
+		if request, ok := container.Resources.Requests[name]; ok {
+			usage[quotaNames[0]] = request
+		} else if hasLimit {
+			usage[quotaNames[0]] = limit
+		}
+	}
+	return usage
+}
+
+func addResourceList(list corev1.ResourceList, other corev1.ResourceList) {
+	for name, quantity := range other {
+		if current, ok := list[name]; ok {
+			current.Add(quantity)
+			list[name] = current
+		} else {
+			list[name] = quantity.DeepCopy()
+		}
+	}
+}
@@ -0,0 +1,74 @@
+// Copyright DataStax, Inc.
+// Please see the included license file for details.
+
+package reconciliation
+
+import (
+	"testing"
+
+	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestGetExpectedResourceUsage(t *testing.T) {
+	rackResources := corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+		},
+	}
+	dc := &api.CassandraDatacenter{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dc1",
+			Namespace: "test",
+		},
+		Spec: api.CassandraDatacenterSpec{
+			ClusterName:   "cluster1",
+			ServerType:    "cassandra",
+			ServerVersion: "3.11.7",
+			Size:          3,
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("1"),
+					corev1.ResourceMemory: resource.MustParse("2Gi"),
+				},
+			},
+			SystemLoggerResources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse("100m"),
+					corev1.ResourceMemory: resource.MustParse("128Mi"),
+				},
+			},
+			Racks: []api.Rack{{Name: "r1"}, {Name: "r2", Resources: &rackResources}},
+			StorageConfig: api.StorageConfig{
+				CassandraDataVolumeClaimSpec: &corev1.PersistentVolumeClaimSpec{
+					Resources: corev1.ResourceRequirements{
+						Requests: corev1.ResourceList{
+							corev1.ResourceStorage: resource.MustParse("10Gi"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	usage, err := GetExpectedResourceUsage(dc)
+	assert.NoError(t, err)
+
+	// r1 has two nodes and r2 one, each with a cassandra and a logger container
+	expected := map[corev1.ResourceName]string{
+		corev1.ResourceRequestsCPU:            "4300m",
+		corev1.ResourceRequestsMemory:         "8576Mi",
+		corev1.ResourceRequestsStorage:        "30Gi",
+		corev1.ResourcePersistentVolumeClaims: "3",
+		corev1.ResourcePods:                   "3",
+	}
+	for name, quantity := range expected {
+		actual := usage[name]
+		want := resource.MustParse(quantity)
+		assert.Equal(t, 0, want.Cmp(actual), "%s is %s, expected %s", name, actual.String(), quantity)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
 	"github.com/k8ssandra/cass-operator/operator/pkg/utils"
@@ -62,6 +63,7 @@ func buildDefaultSuperuserSecret(dc *api.CassandraDatacenter) (*corev1.Secret, e
 			ObjectMeta: metav1.ObjectMeta{
 				Name:      secretNamespacedName.Name,
 				Namespace: secretNamespacedName.Namespace,
+				Labels:    dc.GetSuperuserSecretLabels(),
 			},
 		}
 		username := dc.Spec.ClusterName + "-superuser"
@@ -71,8 +73,8 @@ func buildDefaultSuperuserSecret(dc *api.CassandraDatacenter) (*corev1.Secret, e
 		}
 
 		secret.Data = map[string][]byte{
-			"username": []byte(username),
-			"password": []byte(password),
+			api.SuperuserSecretUsernameKey: []byte(username),
+			api.SuperuserSecretPasswordKey: []byte(password),
 		}
 	}
 
@@ -131,11 +133,38 @@ func (rc *ReconciliationContext) retrieveSuperuserSecretOrCreateDefault() (*core
 		} else {
 			return nil, retrieveErr
 		}
+	} else if dc.ShouldGenerateSuperuserSecret() {
+		if err := rc.addMissingSuperuserSecretLabels(secret); err != nil {
+			return nil, err
+		}
 	}
 
 	return secret, nil
 }
 
+// addMissingSuperuserSecretLabels labels a superuser secret generated by an older version of
+// the operator. Existing labels are left alone, as the secret is shared by the datacenters of
+// the cluster and the datacenter label refers to the one that generated it.
+func (rc *ReconciliationContext) addMissingSuperuserSecretLabels(secret *corev1.Secret) error {
+	patch := client.MergeFrom(secret.DeepCopy())
+
+	updated := false
+	for k, v := range rc.Datacenter.GetSuperuserSecretLabels() {
+		if _, ok := secret.Labels[k]; !ok {
+			if secret.Labels == nil {
+				secret.Labels = map[string]string{}
+			}
+			secret.Labels[k] = v
+			updated = true
+		}
+	}
+
+	if !updated {
+		return nil
+	}
+	return rc.Client.Patch(rc.Ctx, secret, patch)
+}
+
 func (rc *ReconciliationContext) createInternodeCACredential() (*corev1.Secret, error) {
 	secret := &corev1.Secret{
 		TypeMeta: metav1.TypeMeta{
@@ -234,7 +263,7 @@ func validateCassandraUserSecretContent(dc *api.CassandraDatacenter, secret *cor
 		}
 		errorPrefix := fmt.Sprintf("Validation failed for user secret: %s", namespacedName.String())
 
-		for _, key := range []string{"username", "password"} {
+		for _, key := range []string{api.SuperuserSecretUsernameKey, api.SuperuserSecretPasswordKey} {
 			value, ok := secret.Data[key]
 			if !ok {
 				errs = append(errs, fmt.Errorf("%s Missing key: %s", errorPrefix, key))
@@ -5,12 +5,14 @@ package reconciliation
 
 import (
 	"fmt"
+	"reflect"
 	"testing"
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	api "github.com/k8ssandra/cass-operator/operator/pkg/apis/cassandra/v1beta1"
+	"github.com/k8ssandra/cass-operator/operator/pkg/oplabels"
 )
 
 func Test_buildDefaultSuperuserSecret(t *testing.T) {
@@ -43,6 +45,15 @@ func Test_buildDefaultSuperuserSecret(t *testing.T) {
 		if len(errors) > 0 {
 			t.Errorf("expected default secret to be valid, but was not: %w", errors[0])
 		}
+
+		expectedLabels := map[string]string{
+			api.ClusterLabel:        "exampleCluster",
+			api.DatacenterLabel:     "exampleDC",
+			oplabels.ManagedByLabel: oplabels.ManagedByLabelValue,
+		}
+		if !reflect.DeepEqual(secret.ObjectMeta.Labels, expectedLabels) {
+			t.Errorf("expected default secret labels %v but was %v", expectedLabels, secret.ObjectMeta.Labels)
+		}
 	})
 
 	t.Run("test default superuser secret not created when explicitly defined", func(t *testing.T) {